@@ -0,0 +1,165 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// An AccessionNumber identifies an EDGAR filing, e.g. 0001357521-18-000008 for
+// the 8th filing submitted in 2018 by the filer agent with CIK 1357521.
+type AccessionNumber struct {
+	// FilerCIK is the CIK of the filer agent that submitted the filing, which
+	// may differ from the CIK of every company of the filing.
+	FilerCIK int
+
+	// Year is the two-digit year the filing was submitted.
+	Year int
+
+	// Sequence is the sequence number of the filing among the filer agent's
+	// filings of the year.
+	Sequence int
+}
+
+// accessionNumberSuffixes are the suffixes of EDGAR filenames named after an
+// accession number.
+var accessionNumberSuffixes = []string{
+	"-index-headers.html",
+	"-index.html",
+	"-index.htm",
+	".hdr.sgml",
+	".txt",
+}
+
+// ParseAccessionNumber parses s as an accession number with or without dashes,
+// e.g. 0001357521-18-000008 or 000135752118000008, or as the path or URL of a
+// file of a filing, e.g. edgar/data/1000045/0001357521-18-000008.txt.
+func ParseAccessionNumber(s string) (AccessionNumber, error) {
+	base := path.Base(strings.TrimSuffix(s, "/"))
+	for _, suffix := range accessionNumberSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			base = strings.TrimSuffix(base, suffix)
+			break
+		}
+	}
+
+	digits := base
+	if strings.Contains(base, "-") {
+		parts := strings.Split(base, "-")
+		if len(parts) != 3 || len(parts[0]) != 10 || len(parts[1]) != 2 || len(parts[2]) != 6 {
+			return AccessionNumber{}, fmt.Errorf("sec.ParseAccessionNumber: invalid accession number %q", s)
+		}
+		digits = strings.Join(parts, "")
+	}
+	if len(digits) != 18 || strings.Trim(digits, "0123456789") != "" {
+		return AccessionNumber{}, fmt.Errorf("sec.ParseAccessionNumber: invalid accession number %q", s)
+	}
+
+	filerCIK, _ := strconv.Atoi(digits[:10])
+	year, _ := strconv.Atoi(digits[10:12])
+	sequence, _ := strconv.Atoi(digits[12:])
+	return AccessionNumber{FilerCIK: filerCIK, Year: year, Sequence: sequence}, nil
+}
+
+// String returns the accession number with dashes, e.g. 0001357521-18-000008.
+func (a AccessionNumber) String() string {
+	return fmt.Sprintf("%010d-%02d-%06d", a.FilerCIK, a.Year, a.Sequence)
+}
+
+// Compare returns -1, 0 or +1 depending on whether a sorts before, with or
+// after b, in the order of their strings.
+func (a AccessionNumber) Compare(b AccessionNumber) int {
+	return cmp.Or(
+		cmp.Compare(a.FilerCIK, b.FilerCIK),
+		cmp.Compare(a.Year, b.Year),
+		cmp.Compare(a.Sequence, b.Sequence),
+	)
+}
+
+// NoDashes returns the accession number without dashes, e.g.
+// 000135752118000008, as used in the names of filing folders.
+func (a AccessionNumber) NoDashes() string {
+	return fmt.Sprintf("%010d%02d%06d", a.FilerCIK, a.Year, a.Sequence)
+}
+
+// FolderPath returns the path of the folder of the filing of company cik
+// relative to the EDGAR archives root.
+func (a AccessionNumber) FolderPath(cik int) string {
+	return fmt.Sprintf("edgar/data/%d/%s/", cik, a.NoDashes())
+}
+
+// IndexPath returns the path of the -index.htm page of the filing of company
+// cik relative to the EDGAR archives root.
+func (a AccessionNumber) IndexPath(cik int) string {
+	return a.FolderPath(cik) + a.String() + "-index.htm"
+}
+
+// IndexJSONPath returns the path of the index.json listing of the folder of
+// the filing of company cik relative to the EDGAR archives root.
+func (a AccessionNumber) IndexJSONPath(cik int) string {
+	return a.FolderPath(cik) + "index.json"
+}
+
+// HeaderPath returns the path of the .hdr.sgml header of the filing of company
+// cik relative to the EDGAR archives root.
+func (a AccessionNumber) HeaderPath(cik int) string {
+	return a.FolderPath(cik) + a.String() + ".hdr.sgml"
+}
+
+// SubmissionPath returns the path of the complete submission text file of the
+// filing of company cik relative to the EDGAR archives root, as listed in the
+// EDGAR index.
+func (a AccessionNumber) SubmissionPath(cik int) string {
+	return fmt.Sprintf("edgar/data/%d/%s.txt", cik, a)
+}
+
+// AccessionNumber returns the accession number of the filing of the EDGAR
+// index entry.
+func (e EDGARIndexEntry) AccessionNumber() (AccessionNumber, error) {
+	return ParseAccessionNumber(e.Filename)
+}
+
+// FilingFolderURL returns the URL for the folder of the filing of company cik.
+func (c *Client) FilingFolderURL(cik int, a AccessionNumber) string {
+	return c.ArchivesURL(a.FolderPath(cik))
+}
+
+// FilingIndexURL returns the URL for the -index.htm page of the filing of
+// company cik.
+func (c *Client) FilingIndexURL(cik int, a AccessionNumber) string {
+	return c.ArchivesURL(a.IndexPath(cik))
+}
+
+// FilingIndexJSONURL returns the URL for the index.json listing of the folder
+// of the filing of company cik.
+func (c *Client) FilingIndexJSONURL(cik int, a AccessionNumber) string {
+	return c.ArchivesURL(a.IndexJSONPath(cik))
+}
+
+// FilingHeaderURL returns the URL for the .hdr.sgml header of the filing of
+// company cik.
+func (c *Client) FilingHeaderURL(cik int, a AccessionNumber) string {
+	return c.ArchivesURL(a.HeaderPath(cik))
+}
+
+// SubmissionURL returns the URL for the complete submission text file of the
+// filing of company cik.
+func (c *Client) SubmissionURL(cik int, a AccessionNumber) string {
+	return c.ArchivesURL(a.SubmissionPath(cik))
+}
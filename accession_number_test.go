@@ -0,0 +1,103 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import "testing"
+
+func TestParseAccessionNumber(t *testing.T) {
+	want := AccessionNumber{FilerCIK: 1357521, Year: 18, Sequence: 8}
+	for _, s := range []string{
+		"0001357521-18-000008",
+		"000135752118000008",
+		"edgar/data/1000045/0001357521-18-000008.txt",
+		"https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008-index.htm",
+		"https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008.hdr.sgml",
+		"https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/",
+	} {
+		got, err := ParseAccessionNumber(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if got != want {
+			t.Fatalf("%q: got %+v, want %+v", s, got, want)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"1357521-18-8",
+		"0001357521-18-00000A",
+		"0001357521-18-000008-1",
+		"00013575211800000",
+	} {
+		if _, err := ParseAccessionNumber(s); err == nil {
+			t.Fatalf("%q: got no error", s)
+		}
+	}
+}
+
+func TestAccessionNumber_Paths(t *testing.T) {
+	a := AccessionNumber{FilerCIK: 1357521, Year: 18, Sequence: 8}
+	if got, want := a.String(), "0001357521-18-000008"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := a.NoDashes(), "000135752118000008"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	c := NewClient(nil)
+	for _, test := range []struct {
+		got, want string
+	}{
+		{c.FilingFolderURL(1000045, a), "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/"},
+		{c.FilingIndexURL(1000045, a), "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008-index.htm"},
+		{c.FilingIndexJSONURL(1000045, a), "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/index.json"},
+		{c.FilingHeaderURL(1000045, a), "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008.hdr.sgml"},
+		{c.SubmissionURL(1000045, a), "https://www.sec.gov/Archives/edgar/data/1000045/0001357521-18-000008.txt"},
+	} {
+		if test.got != test.want {
+			t.Fatalf("got %q, want %q", test.got, test.want)
+		}
+	}
+}
+
+func TestAccessionNumber_Compare(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"0001357521-18-000008", "0001357521-18-000008", 0},
+		{"0001357521-18-000008", "0001357521-18-000010", -1},
+		{"0001357521-19-000001", "0001357521-18-000010", 1},
+		{"0001104659-18-000002", "0001357521-18-000001", -1},
+	} {
+		a, _ := ParseAccessionNumber(test.a)
+		b, _ := ParseAccessionNumber(test.b)
+		if got := a.Compare(b); got != test.want {
+			t.Errorf("%s.Compare(%s): got %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestEDGARIndexEntry_AccessionNumber(t *testing.T) {
+	e := EDGARIndexEntry{CIK: 1000045, Filename: "edgar/data/1000045/0001357521-18-000008.txt"}
+	a, err := e.AccessionNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.SubmissionPath(e.CIK); got != e.Filename {
+		t.Fatalf("got %q, want %q", got, e.Filename)
+	}
+}
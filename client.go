@@ -15,27 +15,230 @@
 package sec
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
-	"github.com/jadefox10200/httpext"
+	"golang.org/x/time/rate"
 )
 
-// A Client is an SEC client.  Its zero value (DefaultClient) is a usable client
-// that uses http.DefaultClient.
+// SEC fair access defaults.
+//
+// See: https://www.sec.gov/os/accessing-edgar-data
+const (
+	DefaultRateLimit = 10
+	DefaultBurst     = 1
+)
+
+// Default EDGAR base URLs.
+const (
+	DefaultArchivesURL  = "https://www.sec.gov/Archives/"
+	DefaultFullIndexURL = DefaultArchivesURL + "edgar/full-index/"
+	DefaultDataURL      = "https://data.sec.gov/"
+)
+
+// ErrMissingUserAgent is returned for requests by a Client without a declared
+// User-Agent.
+var ErrMissingUserAgent = errors.New("sec: missing User-Agent, see WithUserAgent")
+
+// A Client is an SEC client. Clients are created with NewClient and are safe for
+// concurrent use.
 type Client struct {
-	client *http.Client
+	client      *http.Client
+	userAgent   string
+	limiter     *rate.Limiter
+	retryPolicy RetryPolicy
+
+	filingErrorHandler FilingErrorHandler
+	concurrency        int
+	ordered            bool
+
+	archivesRoot   string
+	fullIndexRoot  string
+	dailyIndexRoot string
+	dataRoot       string
+	browseEDGARURL string
+	dailyIndexMode DailyIndexMode
+	indexType      EDGARIndexType
+	indexOrder     IndexOrder
+
+	indexLineErrorHandler func(*IndexLineError) error
+}
+
+// A ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithUserAgent sets the User-Agent sent with every request. The SEC requires
+// it to declare the company and a contact email, e.g.
+// "Sample Company Name AdminContact@<sample company domain>.com".
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRateLimit limits requests to r per second with bursts of up to burst
+// requests. A rate of rate.Inf disables rate limiting.
+func WithRateLimit(r rate.Limit, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(r, burst)
+	}
 }
 
-// NewClient returns a new Client given a HTTP client. The HTTP client will
-// default to http.DefaultClient when nil.
-func NewClient(c *http.Client) *Client {
+// WithArchivesURL sets the root of the EDGAR archives, e.g. an EDGAR mirror or
+// an httptest server. The full-index and daily-index roots default to
+// "edgar/full-index/" and "edgar/daily-index/" under it unless WithFullIndexURL
+// or WithDailyIndexURL are given.
+func WithArchivesURL(url string) ClientOption {
+	return func(c *Client) {
+		c.archivesRoot = withTrailingSlash(url)
+	}
+}
+
+// WithFullIndexURL sets the root of the EDGAR full index.
+func WithFullIndexURL(url string) ClientOption {
+	return func(c *Client) {
+		c.fullIndexRoot = withTrailingSlash(url)
+	}
+}
+
+// WithDailyIndexURL sets the root of the EDGAR daily index.
+func WithDailyIndexURL(url string) ClientOption {
+	return func(c *Client) {
+		c.dailyIndexRoot = withTrailingSlash(url)
+	}
+}
+
+// WithDataURL sets the root of the data.sec.gov APIs.
+func WithDataURL(url string) ClientOption {
+	return func(c *Client) {
+		c.dataRoot = withTrailingSlash(url)
+	}
+}
+
+// NewClient returns a new Client given a HTTP client and options. The HTTP
+// client will default to http.DefaultClient when nil, and requests will be
+// limited to DefaultRateLimit per second unless WithRateLimit is given.
+//
+// Requests fail with ErrMissingUserAgent unless WithUserAgent is given.
+func NewClient(c *http.Client, opts ...ClientOption) *Client {
 	if c == nil {
 		c = http.DefaultClient
 	}
-	rateLimitedClient := *c
-	rateLimitedClient.Transport = httpext.NewLazyRateLimiter(c.Transport, nil)
-	return &Client{&rateLimitedClient}
+	client := &Client{
+		client:         c,
+		limiter:        rate.NewLimiter(DefaultRateLimit, DefaultBurst),
+		retryPolicy:    DefaultRetryPolicy,
+		archivesRoot:   DefaultArchivesURL,
+		dataRoot:       DefaultDataURL,
+		browseEDGARURL: DefaultBrowseEDGARURL,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.fullIndexRoot == "" {
+		client.fullIndexRoot = client.archivesRoot + "edgar/full-index/"
+	}
+	if client.dailyIndexRoot == "" {
+		client.dailyIndexRoot = client.archivesRoot + "edgar/daily-index/"
+	}
+	return client
 }
 
-// DefaultClient is the default Client.
+// DefaultClient is the default Client. It has no User-Agent, so it must be
+// replaced by a client created with WithUserAgent before use.
 var DefaultClient = NewClient(nil)
+
+// ArchivesURL returns the URL for path relative to the EDGAR archives root.
+func (c *Client) ArchivesURL(path string) string {
+	return c.archivesRoot + strings.TrimPrefix(path, "/")
+}
+
+// DataURL returns the URL for path relative to the data.sec.gov root.
+func (c *Client) DataURL(path string) string {
+	return c.dataRoot + strings.TrimPrefix(path, "/")
+}
+
+// EDGARIndexEntryURL returns the URL for the EDGAR index entry.
+func (c *Client) EDGARIndexEntryURL(e EDGARIndexEntry) string {
+	return c.ArchivesURL(e.Filename)
+}
+
+// fullIndexURL returns the URL for the named file of a quarter in the EDGAR
+// full index.
+func (c *Client) fullIndexURL(year, quarter int, name string) string {
+	return c.fullIndexRoot + fullIndexPath(year, quarter, name)
+}
+
+// dailyIndexURL returns the URL for the named index of day in the EDGAR daily
+// index, e.g. "master" for master.20181015.idx.
+func (c *Client) dailyIndexURL(day time.Time, name string) string {
+	return c.dailyIndexRoot + dailyIndexPath(day, name)
+}
+
+// fullIndexPath returns the path of the named file of a quarter relative to the
+// EDGAR full index root.
+func fullIndexPath(year, quarter int, name string) string {
+	return fmt.Sprintf("%d/QTR%d/%s", year, quarter, name)
+}
+
+// dailyIndexPath returns the path of the named index of day relative to the
+// EDGAR daily index root.
+func dailyIndexPath(day time.Time, name string) string {
+	return fmt.Sprintf("%d/QTR%d/%s.%s.idx", day.Year(), quarterOf(day.Month()), name, day.Format("20060102"))
+}
+
+// withTrailingSlash returns url with a trailing slash.
+func withTrailingSlash(url string) string {
+	if strings.HasSuffix(url, "/") {
+		return url
+	}
+	return url + "/"
+}
+
+// get sends a GET request for url with ctx, retrying according to the
+// client's retry policy. It returns an httpext.StatusError when the response
+// has an error status.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.send(req)
+}
+
+// do sends req with the client's User-Agent once the rate limiter allows it.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	// Files of a Mirror are local, so they need neither a User-Agent nor rate
+	// limiting.
+	if req.URL.Scheme == "file" {
+		return c.client.Do(req)
+	}
+
+	if c.userAgent == "" {
+		return nil, ErrMissingUserAgent
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	ctx := req.Context()
+	if err := c.limiter.Wait(ctx); err != nil {
+		// Prefer the context error when the wait was cancelled.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Prefer the context error when the request was cancelled.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return resp, nil
+}
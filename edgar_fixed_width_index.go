@@ -0,0 +1,127 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseEDGARFormIndex parses an EDGAR form index (form.idx) read from r,
+// calling f for each entry.
+func ParseEDGARFormIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
+	return (&EDGARIndexParser{Type: FormIndex}).Parse(r, f)
+}
+
+// ParseEDGARCompanyIndex parses an EDGAR company index (company.idx) read from
+// r, calling f for each entry.
+func ParseEDGARCompanyIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
+	return (&EDGARIndexParser{Type: CompanyIndex}).Parse(r, f)
+}
+
+// ParseEDGARCrawlerIndex parses an EDGAR crawler index (crawler.idx) read from
+// r, calling f for each entry. The filename of each entry is derived from the
+// URL of its filing index page.
+func ParseEDGARCrawlerIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
+	return (&EDGARIndexParser{Type: CrawlerIndex}).Parse(r, f)
+}
+
+// fixedWidthEntryParser returns the parser for the entries of a fixed-width
+// EDGAR index given the line of column labels of its header. The first two
+// columns are split where labels has the label of the second column, and
+// formTypeFirst is whether the form type is the first column. The CIK, date
+// filed and filename or URL columns are located from the end of each line, as
+// company names may overflow their column.
+func fixedWidthEntryParser(labels, secondColumn string, formTypeFirst bool) (func(string) (*EDGARIndexEntry, error), error) {
+	split := strings.Index(labels, secondColumn)
+	if split < 0 {
+		return nil, fmt.Errorf("sec.fixedWidthEntryParser: missing column %q", secondColumn)
+	}
+	return func(line string) (*EDGARIndexEntry, error) {
+		return parseFixedWidthEDGARIndexEntry(line, split, formTypeFirst)
+	}, nil
+}
+
+// parseFixedWidthEDGARIndexEntry parses line as an entry in a fixed-width
+// EDGAR index.
+func parseFixedWidthEDGARIndexEntry(line string, split int, formTypeFirst bool) (*EDGARIndexEntry, error) {
+	// Cut the last three columns from the end of the line.
+	rest := line
+	var cols [3]string
+	for i := len(cols) - 1; i >= 0; i-- {
+		rest = strings.TrimRight(rest, " ")
+		j := strings.LastIndexByte(rest, ' ')
+		if j < 0 {
+			return nil, errors.New("sec.parseFixedWidthEDGARIndexEntry: wrong number of columns for EDGAR index entry")
+		}
+		rest, cols[i] = rest[:j], rest[j+1:]
+	}
+
+	cik, err := strconv.Atoi(cols[0])
+	if err != nil {
+		return nil, err
+	}
+
+	dateFiled, err := parseEDGARIndexDate(cols[1])
+	if err != nil {
+		return nil, err
+	}
+
+	filename := cols[2]
+	if strings.Contains(filename, "://") {
+		if filename, err = filenameFromIndexURL(filename); err != nil {
+			return nil, err
+		}
+	}
+
+	// Split the first two columns.
+	if split > len(rest) {
+		split = len(rest)
+	}
+	first := strings.TrimSpace(rest[:split])
+	second := strings.TrimSpace(rest[split:])
+	companyName, formType := first, second
+	if formTypeFirst {
+		companyName, formType = second, first
+	}
+
+	return &EDGARIndexEntry{
+		CIK:         cik,
+		CompanyName: companyName,
+		FormType:    formType,
+		DateFiled:   dateFiled,
+		Filename:    filename,
+	}, nil
+}
+
+// filenameFromIndexURL returns the filename of the complete submission of the
+// filing whose index page is at url, e.g. edgar/data/1000045/0001357521-18-000008.txt
+// for https://www.sec.gov/Archives/edgar/data/1000045/0001357521-18-000008-index.htm.
+func filenameFromIndexURL(url string) (string, error) {
+	i := strings.Index(url, "edgar/data/")
+	if i < 0 {
+		return "", fmt.Errorf("sec.filenameFromIndexURL: unexpected URL %q", url)
+	}
+	filename := url[i:]
+	for _, suffix := range []string{"-index.htm", "-index.html"} {
+		if strings.HasSuffix(filename, suffix) {
+			return strings.TrimSuffix(filename, suffix) + ".txt", nil
+		}
+	}
+	return filename, nil
+}
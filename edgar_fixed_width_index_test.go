@@ -0,0 +1,108 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleEDGARFormIndex = `Description:           Master Index of EDGAR Dissemination Feed by Form Type
+Last Data Received:    December 31, 2018
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+Cloud HTTP:            https://www.sec.gov/Archives/
+
+ 
+ 
+ 
+Form Type   Company Name                                                  CIK         Date Filed  File Name
+---------------------------------------------------------------------------------------------------------------------------------------------
+4           NICHOLAS FINANCIAL INC                                        1000045     2018-10-15  edgar/data/1000045/0001357521-18-000008.txt         
+SC 13G/A    SAP SE                                                        1000184     2018-10-19  edgar/data/1000184/0001104659-18-062851.txt         
+`
+
+const sampleEDGARCompanyIndex = `Description:           Master Index of EDGAR Dissemination Feed by Company Name
+Last Data Received:    December 31, 2018
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+Cloud HTTP:            https://www.sec.gov/Archives/
+
+ 
+ 
+ 
+Company Name                                                  Form Type   CIK         Date Filed  File Name
+---------------------------------------------------------------------------------------------------------------------------------------------
+NICHOLAS FINANCIAL INC                                        4           1000045     2018-10-15  edgar/data/1000045/0001357521-18-000008.txt         
+SAP SE                                                        SC 13G/A    1000184     2018-10-19  edgar/data/1000184/0001104659-18-062851.txt         
+`
+
+const sampleEDGARCrawlerIndex = `Description:           Daily Index of EDGAR Dissemination Feed by Company Name
+Last Data Received:    December 31, 2018
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+Cloud HTTP:            https://www.sec.gov/Archives/
+
+ 
+ 
+ 
+Company Name                                                  Form Type   CIK         Date Filed  URL
+---------------------------------------------------------------------------------------------------------------------------------------------
+NICHOLAS FINANCIAL INC                                        4           1000045     2018-10-15  https://www.sec.gov/Archives/edgar/data/1000045/0001357521-18-000008-index.htm
+SAP SE                                                        SC 13G/A    1000184     2018-10-19  https://www.sec.gov/Archives/edgar/data/1000184/0001104659-18-062851-index.htm
+`
+
+var sampleFixedWidthEDGARIndexEntries = []EDGARIndexEntry{
+	EDGARIndexEntry{
+		CIK:         1000045,
+		CompanyName: "NICHOLAS FINANCIAL INC",
+		FormType:    "4",
+		DateFiled:   time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		Filename:    "edgar/data/1000045/0001357521-18-000008.txt",
+	},
+	EDGARIndexEntry{
+		CIK:         1000184,
+		CompanyName: "SAP SE",
+		FormType:    "SC 13G/A",
+		DateFiled:   time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC),
+		Filename:    "edgar/data/1000184/0001104659-18-062851.txt",
+	},
+}
+
+func TestParseFixedWidthEDGARIndex(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		index string
+		parse func(r io.Reader, f func(EDGARIndexEntry) error) error
+	}{
+		{"form", sampleEDGARFormIndex, ParseEDGARFormIndex},
+		{"company", sampleEDGARCompanyIndex, ParseEDGARCompanyIndex},
+		{"crawler", sampleEDGARCrawlerIndex, ParseEDGARCrawlerIndex},
+	} {
+		got := []EDGARIndexEntry{}
+		if err := test.parse(strings.NewReader(test.index), func(e EDGARIndexEntry) error {
+			got = append(got, e)
+			return nil
+		}); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if want := sampleFixedWidthEDGARIndexEntries; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %+v, want %+v", test.name, got, want)
+		}
+	}
+}
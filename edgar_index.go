@@ -17,34 +17,208 @@ package sec
 import (
 	"bufio"
 	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jadefox10200/httpext"
 )
 
-// ParseEDGARIndex parses an EDGAR index read from r, calling f for each entry.
-func ParseEDGARIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
-	scanner := bufio.NewScanner(r)
+// A DailyIndexMode selects when the daily index is read instead of the full
+// index.
+type DailyIndexMode int
+
+// Daily index modes.
+const (
+	// DailyIndexAuto reads the daily index for the current quarter, whose full
+	// index lags behind, and for ranges of less than two weeks in a quarter.
+	DailyIndexAuto DailyIndexMode = iota
+	// DailyIndexNever always reads the full index.
+	DailyIndexNever
+	// DailyIndexAlways always reads the daily index.
+	DailyIndexAlways
+)
+
+// dailyIndexMaxRange is the longest range in a quarter for which DailyIndexAuto
+// reads the daily index.
+const dailyIndexMaxRange = 14 * 24 * time.Hour
+
+// WithDailyIndex sets when the daily index is read instead of the full index.
+// It defaults to DailyIndexAuto.
+func WithDailyIndex(mode DailyIndexMode) ClientOption {
+	return func(c *Client) {
+		c.dailyIndexMode = mode
+	}
+}
+
+// An IndexOrder is the order in which EDGAR index entries are returned.
+type IndexOrder int
+
+// Index orders.
+const (
+	// IndexOrderFile returns entries in the order of the index files, which
+	// are read newest first.
+	IndexOrderFile IndexOrder = iota
+	// IndexOrderAscending returns entries by ascending date filed and then
+	// accession number.
+	IndexOrderAscending
+	// IndexOrderDescending returns entries by descending date filed and then
+	// accession number.
+	IndexOrderDescending
+)
 
-	// Skip 11 lines.
-	for i := 0; scanner.Scan() && i < 11; i++ {
+// WithIndexOrder sets the order in which EDGAR index entries are returned. It
+// defaults to IndexOrderFile. The other orders buffer the selected entries of
+// each index file to sort them, and fail on entries whose filename has no
+// accession number.
+func WithIndexOrder(o IndexOrder) ClientOption {
+	return func(c *Client) {
+		c.indexOrder = o
 	}
+}
 
-	// Parse index entries.
-	for scanner.Scan() {
-		e, err := ParseEDGARIndexEntry(scanner.Text())
+// sortEDGARIndexEntries sorts entries by date filed and then accession number,
+// in descending order when desc is true. It fails when the filename of an
+// entry has no accession number.
+func sortEDGARIndexEntries(entries []EDGARIndexEntry, desc bool) error {
+	type sortEntry struct {
+		entry           EDGARIndexEntry
+		accessionNumber AccessionNumber
+	}
+	sorted := make([]sortEntry, len(entries))
+	for i, e := range entries {
+		a, err := e.AccessionNumber()
 		if err != nil {
 			return err
 		}
+		sorted[i] = sortEntry{e, a}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b sortEntry) int {
+		c := a.entry.DateFiled.Compare(b.entry.DateFiled)
+		if c == 0 {
+			c = a.accessionNumber.Compare(b.accessionNumber)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	for i, e := range sorted {
+		entries[i] = e.entry
+	}
+	return nil
+}
 
-		if err := f(*e); err != nil {
+// ParseEDGARIndex parses an EDGAR index read from r, calling f for each entry.
+// It stops with an *IndexLineError at the first line that cannot be parsed.
+func ParseEDGARIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
+	return (&EDGARIndexParser{Type: MasterIndex}).Parse(r, f)
+}
+
+// An IndexLineError records a line of an EDGAR index that cannot be parsed.
+type IndexLineError struct {
+	Line int // Line number, starting at 1.
+	Text string
+	Err  error
+}
+
+func (e *IndexLineError) Error() string {
+	return fmt.Sprintf("sec: EDGAR index line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *IndexLineError) Unwrap() error {
+	return e.Err
+}
+
+// An EDGARIndexParser parses EDGAR indexes.
+type EDGARIndexParser struct {
+	// Type is the type of the index.
+	Type EDGARIndexType
+
+	// OnLineError is called for each line that cannot be parsed, and parsing
+	// stops when it returns an error. Parsing stops at the first line that
+	// cannot be parsed when nil.
+	OnLineError func(*IndexLineError) error
+}
+
+// WithIndexLineErrorHandler sets the handler for lines of EDGAR indexes that
+// cannot be parsed (see EDGARIndexParser.OnLineError), e.g. to skip and log
+// them. Without a handler, every method reading the index, such as
+// GetEDGARIndexEntries, QueryEDGARIndexEntries, IndexEntries and
+// GetForm4Filings, stops with an *IndexLineError at the first such line.
+func WithIndexLineErrorHandler(h func(*IndexLineError) error) ClientOption {
+	return func(c *Client) {
+		c.indexLineErrorHandler = h
+	}
+}
+
+// Parse parses an EDGAR index read from r, calling f for each entry. The header
+// is skipped up to the line of dashes below the column labels, and blank lines
+// are ignored. Lines may be of any length.
+func (p *EDGARIndexParser) Parse(r io.Reader, f func(EDGARIndexEntry) error) error {
+	br := bufio.NewReader(r)
+
+	var parse func(string) (*EDGARIndexEntry, error)
+	labels := ""
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
 			return err
 		}
+		if line == "" && err == io.EOF {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			// Skip blank lines.
+		case parse == nil:
+			// Skip the header, which ends with a line of dashes below the
+			// column labels.
+			if strings.Trim(line, "-") != "" {
+				labels = line
+				break
+			}
+			var err error
+			if parse, err = p.Type.entryParser(labels); err != nil {
+				return err
+			}
+		default:
+			e, err := parse(line)
+			if err != nil {
+				lineErr := &IndexLineError{Line: n, Text: line, Err: err}
+				if p.OnLineError == nil {
+					return lineErr
+				}
+				if err := p.OnLineError(lineErr); err != nil {
+					return err
+				}
+				break
+			}
+
+			if err := f(*e); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
 	}
 
-	return scanner.Err()
+	if parse == nil && labels != "" {
+		return errors.New("sec.EDGARIndexParser.Parse: missing EDGAR index header")
+	}
+	return nil
 }
 
 // GetEDGARIndexEntries gets EDGAR index entries between start and end, calling f for
@@ -57,94 +231,363 @@ func GetEDGARIndexEntries(start, end time.Time, f func(EDGARIndexEntry) error) e
 	return DefaultClient.GetEDGARIndexEntries(start, end, f)
 }
 
+// GetEDGARIndexEntriesContext is like GetEDGARIndexEntries but uses ctx for
+// every request and stops with ctx.Err() when ctx is done.
+//
+// GetEDGARIndexEntriesContext is a wrapper around
+// DefaultClient.GetEDGARIndexEntriesContext.
+func GetEDGARIndexEntriesContext(ctx context.Context, start, end time.Time, f func(EDGARIndexEntry) error) error {
+	return DefaultClient.GetEDGARIndexEntriesContext(ctx, start, end, f)
+}
+
+// QueryEDGARIndexEntries gets the EDGAR index entries selected by q, calling f
+// for each entry.
+//
+// QueryEDGARIndexEntries is a wrapper around
+// DefaultClient.QueryEDGARIndexEntries.
+func QueryEDGARIndexEntries(ctx context.Context, q IndexQuery, f func(EDGARIndexEntry) error) error {
+	return DefaultClient.QueryEDGARIndexEntries(ctx, q, f)
+}
+
+// IndexEntries returns an iterator over the EDGAR index entries selected by q.
+//
+// IndexEntries is a wrapper around DefaultClient.IndexEntries.
+func IndexEntries(ctx context.Context, q IndexQuery) iter.Seq2[EDGARIndexEntry, error] {
+	return DefaultClient.IndexEntries(ctx, q)
+}
+
 // GetEDGARIndexEntries gets EDGAR index entries between start and end, calling
 // f for each entry. The end time will default to the current time when zero.
 //
+// Reading stops with an *IndexLineError at the first line of the index that
+// cannot be parsed unless the client has a handler for such lines (see
+// WithIndexLineErrorHandler).
+//
 // See: https://www.sec.gov/edgar/searchedgar/accessing-edgar-data.htm
 func (c *Client) GetEDGARIndexEntries(start, end time.Time, f func(EDGARIndexEntry) error) error {
+	return c.GetEDGARIndexEntriesContext(context.Background(), start, end, f)
+}
+
+// GetEDGARIndexEntriesContext is like GetEDGARIndexEntries but uses ctx for
+// every request and stops with ctx.Err() when ctx is done.
+func (c *Client) GetEDGARIndexEntriesContext(ctx context.Context, start, end time.Time, f func(EDGARIndexEntry) error) error {
 	// Use DefaultClient if nil.
 	if c == nil {
 		c = DefaultClient
 	}
 
+	return c.getEDGARIndexEntries(ctx, c.indexType, IndexQuery{Start: start, End: end}, f)
+}
+
+// QueryEDGARIndexEntries gets the EDGAR index entries selected by q, calling f
+// for each entry. The query is applied while the index is read.
+//
+// Reading stops with an *IndexLineError at the first line of the index that
+// cannot be parsed unless the client has a handler for such lines (see
+// WithIndexLineErrorHandler).
+func (c *Client) QueryEDGARIndexEntries(ctx context.Context, q IndexQuery, f func(EDGARIndexEntry) error) error {
+	// Use DefaultClient if nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	return c.getEDGARIndexEntries(ctx, c.indexType, q, f)
+}
+
+// getEDGARIndexEntries gets the entries of EDGAR index files of type t selected
+// by q, calling f for each entry in the client's IndexOrder.
+func (c *Client) getEDGARIndexEntries(ctx context.Context, t EDGARIndexType, q IndexQuery, f func(EDGARIndexEntry) error) error {
 	// Default the end time to the current time when zero.
+	start, end := q.Start, q.End
 	if end.IsZero() {
 		end = time.Now()
 	}
 
-	for year := end.Year(); year >= start.Year(); year-- {
-		endQuarter := 4
-		if year == end.Year() {
-			switch end.Month() {
-			case 1, 2, 3:
-				endQuarter = 1
-			case 4, 5, 6:
-				endQuarter = 2
-			case 7, 8, 9:
-				endQuarter = 3
-			case 10, 11, 12:
-				endQuarter = 4
+	files := c.edgarIndexFiles(t, start, end)
+	if c.indexOrder == IndexOrderAscending {
+		slices.Reverse(files)
+	}
+
+	match := q.matcher()
+	for _, file := range files {
+		// Buffer the entries of each file to sort them, as files cover
+		// disjoint dates.
+		var entries []EDGARIndexEntry
+		if err := c.readEDGARIndex(ctx, file, func(e EDGARIndexEntry) error {
+			if e.DateFiled.Before(start) || e.DateFiled.After(end) || !match(e) {
+				return nil
+			}
+			if c.indexOrder != IndexOrderFile {
+				entries = append(entries, e)
+				return nil
 			}
+			return f(e)
+		}); err != nil {
+			return err
 		}
 
-		startQuarter := 1
-		if year == start.Year() {
-			switch start.Month() {
-			case 1, 2, 3:
-				startQuarter = 1
-			case 4, 5, 6:
-				startQuarter = 2
-			case 7, 8, 9:
-				startQuarter = 3
-			case 10, 11, 12:
-				startQuarter = 4
+		if err := sortEDGARIndexEntries(entries, c.indexOrder == IndexOrderDescending); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(e); err != nil {
+				return err
 			}
 		}
+	}
 
-		for quarter := endQuarter; quarter >= startQuarter; quarter-- {
-			url := fmt.Sprintf("https://www.sec.gov/Archives/edgar/full-index/%d/QTR%d/master.gz", year, quarter)
-			resp, err := c.client.Get(url)
-			if err != nil {
-				return err
+	return nil
+}
+
+// An edgarIndexFile is an EDGAR index file to read.
+type edgarIndexFile struct {
+	url       string
+	indexType EDGARIndexType
+
+	// path is the path of the file relative to the EDGAR archives root, e.g.
+	// "edgar/full-index/2018/QTR4/master.gz".
+	path string
+
+	// final is the time after which the file no longer changes: the end of a
+	// quarter for the full index, and the day itself for the daily index,
+	// whose files are only published once complete.
+	final time.Time
+
+	// optional is whether the file may not exist, such as the daily index of
+	// a weekend or holiday.
+	optional bool
+
+	// fallback is the file read instead when the file does not exist, such
+	// as the gzipped variant of a daily index file.
+	fallback *edgarIndexFile
+}
+
+// edgarIndexFiles returns the EDGAR index files of type t covering start to
+// end, newest first. Quarters are read from the full index unless the client's
+// DailyIndexMode selects the daily index for them.
+func (c *Client) edgarIndexFiles(t EDGARIndexType, start, end time.Time) []edgarIndexFile {
+	first, last := date(start), date(end)
+	current := quarterStart(date(time.Now()))
+
+	var files []edgarIndexFile
+	for q := quarterStart(last); !q.Before(quarterStart(first)); q = q.AddDate(0, -3, 0) {
+		year, quarter := q.Year(), quarterOf(q.Month())
+
+		// Clamp the range to the quarter.
+		from, to := first, last
+		if from.Before(q) {
+			from = q
+		}
+		if next := q.AddDate(0, 3, -1); to.After(next) {
+			to = next
+		}
+
+		daily := false
+		if t.hasDailyIndex() {
+			switch c.dailyIndexMode {
+			case DailyIndexAuto:
+				daily = !q.Before(current) || to.Sub(from) < dailyIndexMaxRange
+			case DailyIndexAlways:
+				daily = true
 			}
-			if httpext.IsErrorStatus(resp.StatusCode) {
-				resp.Body.Close()
-				return httpext.StatusError{
-					URL:        url,
-					StatusCode: resp.StatusCode,
-				}
+		}
+		if !daily {
+			name := t.fullIndexFilename()
+			files = append(files, edgarIndexFile{
+				url:       c.fullIndexURL(year, quarter, name),
+				indexType: t,
+				path:      "edgar/full-index/" + fullIndexPath(year, quarter, name),
+				final:     q.AddDate(0, 3, 0),
+			})
+			continue
+		}
+
+		for day := to; !day.Before(from); day = day.AddDate(0, 0, -1) {
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+				continue
 			}
+			// Some days are only published gzipped.
+			url, path := c.dailyIndexURL(day, t.String()), "edgar/daily-index/"+dailyIndexPath(day, t.String())
+			files = append(files, edgarIndexFile{
+				url:       url,
+				indexType: t,
+				path:      path,
+				final:     day,
+				optional:  true,
+				fallback: &edgarIndexFile{
+					url:       url + ".gz",
+					indexType: t,
+					path:      path + ".gz",
+					final:     day,
+					optional:  true,
+				},
+			})
+		}
+	}
+	return files
+}
 
-			zr, err := gzip.NewReader(resp.Body)
-			if err != nil {
-				resp.Body.Close()
-				if err == io.EOF {
+// readEDGARIndex reads the EDGAR index file, which may be gzipped, calling f
+// for each entry. Reads failing partway through the file are retried according
+// to the client's retry policy, skipping the lines already handled.
+func (c *Client) readEDGARIndex(ctx context.Context, file edgarIndexFile, f func(EDGARIndexEntry) error) error {
+	// handled is the number of entries and malformed lines handled by the
+	// previous attempts, which are skipped when the file is read again.
+	handled := 0
+	for attempt := 1; ; attempt++ {
+		n := 0
+		skip := func() bool {
+			if n++; n <= handled {
+				return true
+			}
+			handled = n
+			return false
+		}
+		p := EDGARIndexParser{Type: file.indexType}
+		if h := c.indexLineErrorHandler; h != nil {
+			p.OnLineError = func(err *IndexLineError) error {
+				if skip() {
 					return nil
 				}
-				return err
+				return h(err)
 			}
+		}
 
-			if err := ParseEDGARIndex(zr, func(e EDGARIndexEntry) error {
-				if !e.DateFiled.Before(start) && !e.DateFiled.After(end) {
-					return f(e)
-				}
+		retry, err := c.readEDGARIndexFile(ctx, file, p, func(e EDGARIndexEntry) error {
+			if skip() {
 				return nil
-			}); err != nil {
-				zr.Close()
-				resp.Body.Close()
+			}
+			if err := ctx.Err(); err != nil {
 				return err
 			}
+			return f(e)
+		})
+		if !retry || attempt >= c.retryPolicy.MaxAttempts {
+			return err
+		}
 
-			if err := zr.Close(); err != nil {
-				resp.Body.Close()
-				return err
+		t := time.NewTimer(c.retryPolicy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// readEDGARIndexFile reads the EDGAR index file with p, calling f for each
+// entry. It returns whether the read should be retried, which is when it
+// failed reading the response body.
+func (c *Client) readEDGARIndexFile(ctx context.Context, file edgarIndexFile, p EDGARIndexParser, f func(EDGARIndexEntry) error) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	resp, err := c.get(ctx, file.url)
+	if err != nil {
+		if statusErr, ok := err.(httpext.StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+			if file.fallback != nil {
+				return c.readEDGARIndexFile(ctx, *file.fallback, p, f)
 			}
+			if file.optional {
+				return false, nil
+			}
+		}
+		return false, err
+	}
+	defer resp.Body.Close()
+	body := &bodyReader{r: resp.Body}
 
-			if err := resp.Body.Close(); err != nil {
-				return err
+	// Decompress gzipped files.
+	var r io.Reader = bufio.NewReader(body)
+	if magic, err := r.(*bufio.Reader).Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return false, ctxErr
 			}
+			return body.failed(err), err
 		}
+		defer zr.Close()
+		r = zr
 	}
 
-	return nil
+	// Return the errors of f as is, such as the error stopping an iterator
+	// whose loop cancelled ctx before breaking.
+	var fErr error
+	if err := p.Parse(r, func(e EDGARIndexEntry) error {
+		fErr = f(e)
+		return fErr
+	}); err != nil {
+		if fErr != nil {
+			return false, err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return false, ctxErr
+		}
+		return body.failed(err), err
+	}
+
+	return false, resp.Body.Close()
+}
+
+// A bodyReader reads a response body, recording the first error other than
+// io.EOF.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// failed returns whether err comes from reading the body.
+func (b *bodyReader) failed(err error) bool {
+	return b.err != nil && errors.Is(err, b.err)
+}
+
+// date returns the date of t as midnight UTC, like EDGARIndexEntry.DateFiled.
+func date(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// quarterOf returns the quarter of month.
+func quarterOf(month time.Month) int {
+	return (int(month)-1)/3 + 1
+}
+
+// quarterStart returns the first day of the quarter of t.
+func quarterStart(t time.Time) time.Time {
+	return time.Date(t.Year(), time.Month(3*quarterOf(t.Month())-2), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// IndexEntries returns an iterator over the EDGAR index entries selected by q.
+// Entries are streamed as the index is read, and the index is closed when the
+// loop breaks. An error, such as an *IndexLineError for a line of the index
+// that cannot be parsed without a handler for such lines (see
+// WithIndexLineErrorHandler), ends the iteration.
+func (c *Client) IndexEntries(ctx context.Context, q IndexQuery) iter.Seq2[EDGARIndexEntry, error] {
+	return func(yield func(EDGARIndexEntry, error) bool) {
+		// Never yield again once the loop broke, whatever the error.
+		stopped := false
+		if err := c.QueryEDGARIndexEntries(ctx, q, func(e EDGARIndexEntry) error {
+			if !yield(e, nil) {
+				stopped = true
+				return errStopIteration
+			}
+			return nil
+		}); err != nil && !stopped {
+			yield(EDGARIndexEntry{}, err)
+		}
+	}
 }
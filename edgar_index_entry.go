@@ -30,35 +30,48 @@ type EDGARIndexEntry struct {
 	Filename    string
 }
 
-// ParseEDGARIndexEntry parses s as an entry in an EDGAR index.
+// ParseEDGARIndexEntry parses s as an entry in a pipe-delimited EDGAR index.
+// Company names containing pipes are recovered by locating the other columns
+// from either end of s.
 func ParseEDGARIndexEntry(s string) (*EDGARIndexEntry, error) {
 	cols := strings.Split(s, "|")
-	if len(cols) != 5 {
+	if len(cols) < 5 {
 		return nil, errors.New("sec.ParseEDGARIndexEntry: wrong number of columns for EDGAR index entry")
 	}
+	n := len(cols)
 
-	cik, err := strconv.Atoi(cols[0])
+	cik, err := strconv.Atoi(strings.TrimSpace(cols[0]))
 	if err != nil {
 		return nil, err
 	}
 
-	dateFiled, err := time.Parse("2006-01-02", cols[3])
+	dateFiled, err := parseEDGARIndexDate(strings.TrimSpace(cols[n-2]))
 	if err != nil {
 		return nil, err
 	}
 
 	return &EDGARIndexEntry{
 		CIK:         cik,
-		CompanyName: cols[1],
-		FormType:    cols[2],
+		CompanyName: strings.Join(cols[1:n-3], "|"),
+		FormType:    cols[n-3],
 		DateFiled:   dateFiled,
-		Filename:    cols[4],
+		Filename:    strings.TrimSpace(cols[n-1]),
 	}, nil
 }
 
-// URL returns the URL for the EDGAR index entry.
+// parseEDGARIndexDate parses s as the date filed of an entry in an EDGAR index.
+func parseEDGARIndexDate(s string) (time.Time, error) {
+	// The daily index omits the dashes from dates.
+	if len(s) == len("20060102") {
+		return time.Parse("20060102", s)
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// URL returns the URL for the EDGAR index entry on www.sec.gov. Use
+// Client.EDGARIndexEntryURL for the URL relative to a client's archives root.
 func (e EDGARIndexEntry) URL() string {
-	return "https://www.sec.gov/Archives/" + e.Filename
+	return DefaultArchivesURL + e.Filename
 }
 
 func (e EDGARIndexEntry) String() string {
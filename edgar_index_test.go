@@ -17,15 +17,23 @@ package sec
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"slices"
+	"strings"
+	"syscall"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/jadefox10200/httpext"
 )
 
+const testUserAgent = "Sample Company Name AdminContact@example.com"
+
 const sampleEDGARIndex = `
 Description:           Master Index of EDGAR Dissemination Feed
 Last Data Received:    October 20, 2018
@@ -70,6 +78,50 @@ func TestParseEDGARIndex(t *testing.T) {
 	}
 }
 
+func TestEDGARIndexParser(t *testing.T) {
+	longName := strings.Repeat("A", 100000)
+	index := `Description:           Master Index of EDGAR Dissemination Feed
+Last Data Received:    March 31, 1994
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+
+CIK|Company Name|Form Type|Date Filed|Filename
+--------------------------------------------------------------------------------
+1000045|SMITH | JONES INC|4|2018-10-15|edgar/data/1000045/0001357521-18-000008.txt
+1000046|` + longName + `|4|2018-10-15|edgar/data/1000046/0001357521-18-000009.txt
+1000047|MALFORMED INC|4|not a date|edgar/data/1000047/0001357521-18-000010.txt
+1000184|SAP SE|6-K|2018-10-19|edgar/data/1000184/0001104659-18-062851.txt
+
+`
+	got := []string{}
+	lineErrs := []int{}
+	p := EDGARIndexParser{
+		OnLineError: func(err *IndexLineError) error {
+			lineErrs = append(lineErrs, err.Line)
+			return nil
+		},
+	}
+	if err := p.Parse(strings.NewReader(index), func(e EDGARIndexEntry) error {
+		got = append(got, e.CompanyName)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"SMITH | JONES INC", longName, "SAP SE"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %.40q, want %.40q", got, want)
+	}
+	if want := []int{10}; !reflect.DeepEqual(lineErrs, want) {
+		t.Fatalf("got line errors %v, want %v", lineErrs, want)
+	}
+
+	err := ParseEDGARIndex(strings.NewReader(index), func(e EDGARIndexEntry) error {
+		return nil
+	})
+	if lineErr, ok := err.(*IndexLineError); !ok || lineErr.Line != 10 {
+		t.Fatalf("got error %v, want error on line 10", err)
+	}
+}
+
 func TestClient_GetEDGARIndexEntries(t *testing.T) {
 	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
 		r, w := io.Pipe()
@@ -88,7 +140,7 @@ func TestClient_GetEDGARIndexEntries(t *testing.T) {
 		var res http.Response
 		res.Body = r
 		return &res, nil
-	}))
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
 	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
 	got := []EDGARIndexEntry{}
@@ -117,3 +169,412 @@ func TestClient_GetEDGARIndexEntries(t *testing.T) {
 		t.Fatalf("got %+v, want %+v", got, want)
 	}
 }
+
+func TestClient_GetEDGARIndexEntriesContext(t *testing.T) {
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write([]byte(sampleEDGARIndex)); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		var res http.Response
+		res.Body = io.NopCloser(&buf)
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	n := 0
+	err := c.GetEDGARIndexEntriesContext(ctx, start, end, func(e EDGARIndexEntry) error {
+		n++
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if n != 1 {
+		t.Fatalf("got %d entries, want 1", n)
+	}
+}
+
+func TestClient_GetEDGARIndexEntriesRetryRead(t *testing.T) {
+	// Gzip the index with the first entry and a malformed line flushed
+	// before the second entry.
+	i := strings.Index(sampleEDGARIndex, "1000184|")
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(sampleEDGARIndex[:i] + "malformed\n"))
+	gz.Flush()
+	cut := buf.Len()
+	gz.Write([]byte(sampleEDGARIndex[i:]))
+	gz.Close()
+
+	// Reset the connection after the flushed part of the first response.
+	requests, lineErrs := 0, 0
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		requests++
+		var res http.Response
+		res.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+		if requests == 1 {
+			res.Body = io.NopCloser(io.MultiReader(bytes.NewReader(buf.Bytes()[:cut]), iotest.ErrReader(syscall.ECONNRESET)))
+		}
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	}), WithIndexLineErrorHandler(func(*IndexLineError) error {
+		lineErrs++
+		return nil
+	}))
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	got := []int{}
+	if err := c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+		got = append(got, e.CIK)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1000045, 1000184}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if requests != 2 || lineErrs != 1 {
+		t.Fatalf("got %d requests and %d malformed lines, want 2 and 1", requests, lineErrs)
+	}
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	var got string
+	transport := httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("User-Agent")
+		var res http.Response
+		res.Body = io.NopCloser(strings.NewReader(""))
+		return &res, nil
+	})
+	ctx := context.Background()
+
+	if _, err := NewClient(transport).get(ctx, "https://www.sec.gov/"); err != ErrMissingUserAgent {
+		t.Fatalf("got error %v, want %v", err, ErrMissingUserAgent)
+	}
+
+	resp, err := NewClient(transport, WithUserAgent(testUserAgent)).get(ctx, "https://www.sec.gov/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got != testUserAgent {
+		t.Fatalf("got User-Agent %q, want %q", got, testUserAgent)
+	}
+}
+
+func TestClient_WithArchivesURL(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(sampleEDGARIndex))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL+"/mirror"), WithDailyIndex(DailyIndexNever))
+	if got, want := c.EDGARIndexEntryURL(EDGARIndexEntry{Filename: "edgar/data/1000045/0001357521-18-000008.txt"}), srv.URL+"/mirror/edgar/data/1000045/0001357521-18-000008.txt"; got != want {
+		t.Fatalf("got URL %q, want %q", got, want)
+	}
+
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	n := 0
+	if err := c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d entries, want 2", n)
+	}
+	if want := []string{"/mirror/edgar/full-index/2018/QTR4/master.gz"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("got paths %v, want %v", paths, want)
+	}
+}
+
+func TestClient_WithIndexType(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(sampleEDGARCrawlerIndex))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL), WithDailyIndex(DailyIndexNever), WithIndexType(CrawlerIndex))
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	got := []EDGARIndexEntry{}
+	if err := c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := sampleFixedWidthEDGARIndexEntries; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if want := []string{"/edgar/full-index/2018/QTR4/crawler.idx"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("got paths %v, want %v", paths, want)
+	}
+}
+
+// closeRecorder records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestClient_IndexEntries(t *testing.T) {
+	var body *closeRecorder
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(sampleEDGARIndex))
+		gz.Close()
+		body = &closeRecorder{Reader: &buf}
+		var res http.Response
+		res.Body = body
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
+	q := IndexQuery{
+		Start: time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	got := []string{}
+	for e, err := range c.IndexEntries(context.Background(), q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e.CompanyName)
+	}
+	if want := []string{"NICHOLAS FINANCIAL INC", "SAP SE"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	got = got[:0]
+	q.FormTypes = []string{"6-K"}
+	for e, err := range c.IndexEntries(context.Background(), q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e.CompanyName)
+	}
+	if want := []string{"SAP SE"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	for _, err := range c.IndexEntries(context.Background(), q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+	if !body.closed {
+		t.Fatal("body not closed after break")
+	}
+}
+
+func TestClient_IndexEntriesCancelBreak(t *testing.T) {
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		res.Body = io.NopCloser(strings.NewReader(sampleEDGARIndex))
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
+	q := IndexQuery{
+		Start: time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Breaking after cancelling the context must end the iteration without
+	// yielding the context error.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	for _, err := range c.IndexEntries(ctx, q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+		cancel()
+		break
+	}
+	if n != 1 {
+		t.Fatalf("got %d entries, want 1", n)
+	}
+}
+
+const sampleDailyEDGARIndex = `Description:           Daily Index of EDGAR Dissemination Feed by Company Name
+Last Data Received:    Oct 15, 2018
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+ 
+ 
+ 
+ 
+CIK|Company Name|Form Type|Date Filed|File Name
+--------------------------------------------------------------------------------
+1000045|NICHOLAS FINANCIAL INC|4|20181015|edgar/data/1000045/0001357521-18-000008.txt
+`
+
+func TestClient_GetEDGARIndexEntriesDailyIndex(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/edgar/daily-index/2018/QTR4/master.20181015.idx":
+			w.Write([]byte(sampleDailyEDGARIndex))
+		case "/edgar/daily-index/2018/QTR4/master.20181016.idx.gz":
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(strings.Replace(sampleDailyEDGARIndex, "20181015", "20181016", 1)))
+			gz.Close()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL))
+	start := time.Date(2018, 10, 12, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 16, 0, 0, 0, 0, time.UTC)
+	got := []time.Time{}
+	if err := c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+		got = append(got, e.DateFiled)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{end, end.AddDate(0, 0, -1)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if want := []string{
+		"/edgar/daily-index/2018/QTR4/master.20181016.idx",
+		"/edgar/daily-index/2018/QTR4/master.20181016.idx.gz",
+		"/edgar/daily-index/2018/QTR4/master.20181015.idx",
+		"/edgar/daily-index/2018/QTR4/master.20181012.idx",
+		"/edgar/daily-index/2018/QTR4/master.20181012.idx.gz",
+	}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("got paths %v, want %v", paths, want)
+	}
+}
+
+func TestClient_edgarIndexFiles(t *testing.T) {
+	c := NewClient(nil)
+	start := time.Date(2018, 9, 25, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	got := []string{}
+	for _, file := range c.edgarIndexFiles(MasterIndex, start, end) {
+		got = append(got, file.url)
+	}
+	if want := []string{
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR4/master.20181001.idx",
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR3/master.20180928.idx",
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR3/master.20180927.idx",
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR3/master.20180926.idx",
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR3/master.20180925.idx",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	start = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	got = got[:0]
+	for _, file := range c.edgarIndexFiles(MasterIndex, start, end) {
+		got = append(got, file.url)
+	}
+	if want := []string{
+		"https://www.sec.gov/Archives/edgar/daily-index/2018/QTR4/master.20181001.idx",
+		"https://www.sec.gov/Archives/edgar/full-index/2018/QTR3/master.gz",
+		"https://www.sec.gov/Archives/edgar/full-index/2018/QTR2/master.gz",
+		"https://www.sec.gov/Archives/edgar/full-index/2018/QTR1/master.gz",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClient_WithIndexOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var day string
+		switch r.URL.Path {
+		case "/edgar/daily-index/2018/QTR3/master.20180928.idx":
+			day = "20180928"
+		case "/edgar/daily-index/2018/QTR4/master.20181001.idx":
+			day = "20181001"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`CIK|Company Name|Form Type|Date Filed|File Name
+--------------------------------------------------------------------------------
+1000184|SAP SE|6-K|` + day + `|edgar/data/1000184/0001104659-18-000002.txt
+1000045|NICHOLAS FINANCIAL INC|4|` + day + `|edgar/data/1000045/0001104659-18-000001.txt
+`))
+	}))
+	defer srv.Close()
+
+	start := time.Date(2018, 9, 28, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	ascending := []string{
+		"20180928 edgar/data/1000045/0001104659-18-000001.txt",
+		"20180928 edgar/data/1000184/0001104659-18-000002.txt",
+		"20181001 edgar/data/1000045/0001104659-18-000001.txt",
+		"20181001 edgar/data/1000184/0001104659-18-000002.txt",
+	}
+	descending := append([]string{}, ascending...)
+	slices.Reverse(descending)
+	for _, test := range []struct {
+		order IndexOrder
+		want  []string
+	}{
+		{IndexOrderAscending, ascending},
+		{IndexOrderDescending, descending},
+	} {
+		c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL), WithIndexOrder(test.order))
+		got := []string{}
+		if err := c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+			got = append(got, e.DateFiled.Format("20060102")+" "+e.Filename)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("order %d: got %v, want %v", test.order, got, test.want)
+		}
+	}
+}
+
+func TestClient_WithIndexOrderMalformedFilename(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`CIK|Company Name|Form Type|Date Filed|File Name
+--------------------------------------------------------------------------------
+1000045|NICHOLAS FINANCIAL INC|4|20181001|edgar/data/1000045/filing.txt
+`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL), WithIndexOrder(IndexOrderAscending))
+	day := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	if err := c.GetEDGARIndexEntries(day, day, func(EDGARIndexEntry) error {
+		t.Fatal("unexpected entry")
+		return nil
+	}); err == nil {
+		t.Fatal("got no error for a filename without an accession number")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import "fmt"
+
+// An EDGARIndexType is a family of EDGAR index files, which list the same
+// filings sorted and formatted differently.
+type EDGARIndexType int
+
+// EDGAR index types.
+const (
+	// MasterIndex is the pipe-delimited index sorted by CIK.
+	MasterIndex EDGARIndexType = iota
+	// FormIndex is the fixed-width index sorted by form type.
+	FormIndex
+	// CompanyIndex is the fixed-width index sorted by company name.
+	CompanyIndex
+	// CrawlerIndex is the fixed-width index sorted by company name, which
+	// links to the filing index pages.
+	CrawlerIndex
+	// XBRLIndex is the pipe-delimited index of filings with XBRL financial
+	// data. It is only published in the full index.
+	XBRLIndex
+)
+
+// WithIndexType sets the type of EDGAR index files that are read. It defaults
+// to MasterIndex.
+func WithIndexType(t EDGARIndexType) ClientOption {
+	return func(c *Client) {
+		c.indexType = t
+	}
+}
+
+func (t EDGARIndexType) String() string {
+	switch t {
+	case MasterIndex:
+		return "master"
+	case FormIndex:
+		return "form"
+	case CompanyIndex:
+		return "company"
+	case CrawlerIndex:
+		return "crawler"
+	case XBRLIndex:
+		return "xbrl"
+	}
+	return fmt.Sprintf("EDGARIndexType(%d)", int(t))
+}
+
+// fullIndexFilename returns the name of the file of the index type in each
+// quarter of the full index.
+func (t EDGARIndexType) fullIndexFilename() string {
+	// The crawler index is not published gzipped.
+	if t == CrawlerIndex {
+		return "crawler.idx"
+	}
+	return t.String() + ".gz"
+}
+
+// hasDailyIndex returns whether the index type is published in the daily
+// index.
+func (t EDGARIndexType) hasDailyIndex() bool {
+	return t != XBRLIndex
+}
+
+// entryParser returns the parser for the entries of an index of the index
+// type given the line of column labels of its header.
+func (t EDGARIndexType) entryParser(labels string) (func(string) (*EDGARIndexEntry, error), error) {
+	switch t {
+	case FormIndex:
+		return fixedWidthEntryParser(labels, "Company Name", true)
+	case CompanyIndex, CrawlerIndex:
+		return fixedWidthEntryParser(labels, "Form Type", false)
+	}
+	return ParseEDGARIndexEntry, nil
+}
@@ -15,6 +15,7 @@
 package sec_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -23,6 +24,7 @@ import (
 )
 
 func ExampleGetEDGARIndexEntries() {
+	sec.DefaultClient = sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
 	end := time.Now()
 	start := end.AddDate(0, -1, 0)
 	if err := sec.GetEDGARIndexEntries(start, end, func(e sec.EDGARIndexEntry) error {
@@ -34,7 +36,7 @@ func ExampleGetEDGARIndexEntries() {
 }
 
 func ExampleClient_GetEDGARIndexEntries() {
-	c := sec.NewClient(nil)
+	c := sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
 	end := time.Now()
 	start := end.AddDate(0, -1, 0)
 	if err := c.GetEDGARIndexEntries(start, end, func(e sec.EDGARIndexEntry) error {
@@ -46,6 +48,7 @@ func ExampleClient_GetEDGARIndexEntries() {
 }
 
 func ExampleGetForm4Filings() {
+	sec.DefaultClient = sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
 	end := time.Now()
 	start := end.AddDate(0, -1, 0)
 	if err := sec.GetForm4Filings(start, end, func(form sec.Form4) error {
@@ -57,7 +60,7 @@ func ExampleGetForm4Filings() {
 }
 
 func ExampleClient_GetForm4Filings() {
-	c := sec.NewClient(nil)
+	c := sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
 	end := time.Now()
 	start := end.AddDate(0, -1, 0)
 	if err := c.GetForm4Filings(start, end, func(form sec.Form4) error {
@@ -67,3 +70,27 @@ func ExampleClient_GetForm4Filings() {
 		log.Fatal(err)
 	}
 }
+
+func ExampleClient_IndexEntries() {
+	c := sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
+	end := time.Now()
+	start := end.AddDate(0, -1, 0)
+	for e, err := range c.IndexEntries(context.Background(), sec.IndexQuery{Start: start, End: end}) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%+v\n", e)
+	}
+}
+
+func ExampleClient_Form4s() {
+	c := sec.NewClient(nil, sec.WithUserAgent("Sample Company Name AdminContact@example.com"))
+	end := time.Now()
+	start := end.AddDate(0, -1, 0)
+	for form, err := range c.Form4s(context.Background(), sec.IndexQuery{Start: start, End: end}) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%+v\n", form)
+	}
+}
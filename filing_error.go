@@ -0,0 +1,155 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A FilingStage is a stage of getting a filing.
+type FilingStage int
+
+// Filing stages.
+const (
+	// FilingStageFetch is sending the HTTP request for the filing.
+	FilingStageFetch FilingStage = iota
+	// FilingStageExtract is extracting the document from the SEC document.
+	FilingStageExtract
+	// FilingStageParse is parsing the extracted document.
+	FilingStageParse
+)
+
+func (s FilingStage) String() string {
+	switch s {
+	case FilingStageFetch:
+		return "fetch"
+	case FilingStageExtract:
+		return "extract"
+	case FilingStageParse:
+		return "parse"
+	}
+	return fmt.Sprintf("FilingStage(%d)", int(s))
+}
+
+// A FilingError records a failure to get the filing of an EDGAR index entry.
+type FilingError struct {
+	Entry EDGARIndexEntry
+	URL   string
+	Stage FilingStage
+	Err   error
+}
+
+func (e *FilingError) Error() string {
+	return "sec: " + e.Stage.String() + " " + e.URL + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FilingError) Unwrap() error {
+	return e.Err
+}
+
+// A FilingErrorAction is what to do with a filing that failed.
+type FilingErrorAction int
+
+// Filing error actions.
+const (
+	// FilingErrorAbort aborts the run with the FilingError.
+	FilingErrorAbort FilingErrorAction = iota
+	// FilingErrorSkip skips the filing and counts it in the FilingErrors
+	// returned at the end of the run.
+	FilingErrorSkip
+	// FilingErrorCollect skips the filing and reports its FilingError in the
+	// FilingErrors returned at the end of the run.
+	FilingErrorCollect
+)
+
+// A FilingErrorHandler decides what to do with a filing that failed. It is
+// never called concurrently.
+type FilingErrorHandler func(*FilingError) FilingErrorAction
+
+// WithFilingErrorHandler sets the handler for filings that fail to be fetched,
+// extracted or parsed. Without a handler, runs abort on the first failed
+// filing, where previously filings that failed to be fetched were dropped
+// silently; use a handler returning FilingErrorSkip for a similar behavior.
+func WithFilingErrorHandler(h FilingErrorHandler) ClientOption {
+	return func(c *Client) {
+		c.filingErrorHandler = h
+	}
+}
+
+// handleFilingError returns the action for err according to the client's
+// filing error handler.
+func (c *Client) handleFilingError(err *FilingError) FilingErrorAction {
+	if c.filingErrorHandler == nil {
+		return FilingErrorAbort
+	}
+	return c.filingErrorHandler(err)
+}
+
+// FilingErrors is the summary of the filings skipped in a run, which is
+// returned at the end of the run when any filing was skipped.
+type FilingErrors struct {
+	// Errors are the filings skipped with FilingErrorCollect.
+	Errors []*FilingError
+
+	// Skipped is the number of filings skipped with FilingErrorSkip per
+	// stage.
+	Skipped map[FilingStage]int
+}
+
+// add records err as skipped with action.
+func (errs *FilingErrors) add(err *FilingError, action FilingErrorAction) {
+	if action == FilingErrorCollect {
+		errs.Errors = append(errs.Errors, err)
+		return
+	}
+	if errs.Skipped == nil {
+		errs.Skipped = make(map[FilingStage]int)
+	}
+	errs.Skipped[err.Stage]++
+}
+
+// Len returns the number of skipped filings.
+func (errs FilingErrors) Len() int {
+	n := len(errs.Errors)
+	for _, skipped := range errs.Skipped {
+		n += skipped
+	}
+	return n
+}
+
+// Stages returns the number of skipped filings per stage.
+func (errs FilingErrors) Stages() map[FilingStage]int {
+	stages := make(map[FilingStage]int)
+	for s, n := range errs.Skipped {
+		stages[s] += n
+	}
+	for _, err := range errs.Errors {
+		stages[err.Stage]++
+	}
+	return stages
+}
+
+func (errs FilingErrors) Error() string {
+	stages := errs.Stages()
+	counts := make([]string, 0, len(stages))
+	for s := FilingStageFetch; s <= FilingStageParse; s++ {
+		if n := stages[s]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", s, n))
+		}
+	}
+	return fmt.Sprintf("sec: skipped %d filings (%s)", errs.Len(), strings.Join(counts, ", "))
+}
@@ -0,0 +1,218 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A FilingDocument is a document in the folder of a filing.
+type FilingDocument struct {
+	// Sequence is the sequence number of the document in the filing, or 0
+	// for files that are not documents of the submission, such as the
+	// complete submission text file.
+	Sequence int
+
+	Type        string
+	Description string
+
+	// Filename is the name of the document relative to the folder of the
+	// filing, e.g. "edgar.xml" or "xslF345X03/edgar.xml" for its rendering.
+	Filename string
+
+	// Size is the size of the document in bytes, or 0 when unknown.
+	Size int64
+
+	URL string
+}
+
+var (
+	// filingIndexTable matches the tables of documents of a filing index.
+	filingIndexTable = regexp.MustCompile(`(?is)<table[^>]*class="tableFile"[^>]*>(.*?)</table>`)
+
+	// filingIndexRow matches the rows of a table.
+	filingIndexRow = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+
+	// filingIndexCell matches the data cells of a row.
+	filingIndexCell = regexp.MustCompile(`(?is)<td[^>]*>(.*?)</td>`)
+
+	// filingIndexHref matches the link of a cell.
+	filingIndexHref = regexp.MustCompile(`(?is)<a[^>]*href="([^"]*)"`)
+
+	// htmlTag matches HTML tags.
+	htmlTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseFilingIndex parses the documents listed by the -index.htm page of a
+// filing read from r. The URL of each document is relative to www.sec.gov.
+func ParseFilingIndex(r io.Reader) ([]FilingDocument, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	tables := filingIndexTable.FindAllSubmatch(b, -1)
+	if tables == nil {
+		return nil, errors.New("sec.ParseFilingIndex: missing document table")
+	}
+
+	base, _ := url.Parse(DefaultArchivesURL)
+	docs := []FilingDocument{}
+	for _, table := range tables {
+		for _, row := range filingIndexRow.FindAllSubmatch(table[1], -1) {
+			// Skip the header row, which has no data cells.
+			cells := filingIndexCell.FindAllSubmatch(row[1], -1)
+			if len(cells) < 5 {
+				continue
+			}
+
+			var href string
+			if m := filingIndexHref.FindSubmatch(cells[2][1]); m != nil {
+				href = html.UnescapeString(string(m[1]))
+			}
+			// Inline XBRL documents link to the inline XBRL viewer.
+			href = strings.TrimPrefix(href, "/ix?doc=")
+			ref, err := url.Parse(href)
+			if err != nil {
+				return nil, err
+			}
+
+			doc := FilingDocument{
+				Description: filingIndexText(cells[1][1]),
+				Filename:    filingDocumentFilename(ref.Path),
+				Type:        filingIndexText(cells[3][1]),
+				URL:         base.ResolveReference(ref).String(),
+			}
+			if seq := filingIndexText(cells[0][1]); seq != "" {
+				if doc.Sequence, err = strconv.Atoi(seq); err != nil {
+					return nil, err
+				}
+			}
+			if size := filingIndexText(cells[4][1]); size != "" {
+				if doc.Size, err = strconv.ParseInt(size, 10, 64); err != nil {
+					return nil, err
+				}
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// filingIndexText returns the text of an HTML cell. Cells of only &nbsp; are
+// blank, as strings.TrimSpace trims non-breaking spaces.
+func filingIndexText(b []byte) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTag.ReplaceAllString(string(b), "")))
+}
+
+// filingDocumentFilename returns the name of the document at p relative to
+// the folder of its filing, which is named after the accession number without
+// dashes.
+func filingDocumentFilename(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		if len(part) == 18 && strings.Trim(part, "0123456789") == "" {
+			return strings.Join(parts[i+1:], "/")
+		}
+	}
+	return path.Base(p)
+}
+
+// filingIndexJSON is the index.json listing of the folder of a filing.
+type filingIndexJSON struct {
+	Directory struct {
+		Item []struct {
+			Name string `json:"name"`
+			Size string `json:"size"`
+		} `json:"item"`
+	} `json:"directory"`
+}
+
+// ParseFilingIndexJSON parses the files listed by the index.json listing of the
+// folder of a filing read from r. The listing only has the filename and size of
+// each file, and includes the index files of the folder. The URL of each file
+// is left empty.
+func ParseFilingIndexJSON(r io.Reader) ([]FilingDocument, error) {
+	var index filingIndexJSON
+	if err := json.NewDecoder(r).Decode(&index); err != nil {
+		return nil, err
+	}
+	docs := make([]FilingDocument, 0, len(index.Directory.Item))
+	for _, item := range index.Directory.Item {
+		doc := FilingDocument{Filename: item.Name}
+		if item.Size != "" {
+			size, err := strconv.ParseInt(item.Size, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			doc.Size = size
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// GetFilingDocuments gets the documents of the filing of company cik.
+//
+// GetFilingDocuments is a wrapper around DefaultClient.GetFilingDocuments.
+func GetFilingDocuments(ctx context.Context, cik int, a AccessionNumber) ([]FilingDocument, error) {
+	return DefaultClient.GetFilingDocuments(ctx, cik, a)
+}
+
+// GetFilingDocuments gets the documents of the filing of company cik from its
+// -index.htm page, falling back to the index.json listing of its folder, which
+// lacks the sequence, type and description of each document. The URL of each
+// document is relative to the client's archives root.
+func (c *Client) GetFilingDocuments(ctx context.Context, cik int, a AccessionNumber) ([]FilingDocument, error) {
+	// Use DefaultClient when nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	docs, err := c.getFilingDocuments(ctx, c.FilingIndexURL(cik, a), ParseFilingIndex)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		var jsonErr error
+		if docs, jsonErr = c.getFilingDocuments(ctx, c.FilingIndexJSONURL(cik, a), ParseFilingIndexJSON); jsonErr != nil {
+			return nil, err
+		}
+	}
+
+	folder := a.FolderPath(cik)
+	for i := range docs {
+		docs[i].URL = c.ArchivesURL(folder + docs[i].Filename)
+	}
+	return docs, nil
+}
+
+// getFilingDocuments gets the documents listed by the file at url using parse.
+func (c *Client) getFilingDocuments(ctx context.Context, url string, parse func(io.Reader) ([]FilingDocument, error)) ([]FilingDocument, error) {
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parse(resp.Body)
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleFilingIndex = `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<title>EDGAR Filing Documents for 0001357521-18-000008</title>
+</head>
+<body>
+<div id="formDiv">
+<div style="padding: 0px 0px 4px 0px; font-size: 12px; margin: 0px 2px 0px 5px; width: 100%; overflow:hidden">
+<p>Document Format Files</p>
+<table class="tableFile" summary="Document Format Files">
+         <tr>
+            <th scope="col" style="width: 5%;"><acronym title="Sequence Number">Seq</acronym></th>
+            <th scope="col" style="width: 40%;">Description</th>
+            <th scope="col" style="width: 20%;">Document</th>
+            <th scope="col" style="width: 10%;">Type</th>
+            <th scope="col">Size</th>
+         </tr>
+         <tr>
+            <td scope="row">1</td>
+            <td scope="row">FORM 4</td>
+            <td scope="row"><a href="/Archives/edgar/data/1000045/000135752118000008/xslF345X03/edgar.xml">edgar.html</a></td>
+            <td scope="row">4</td>
+            <td scope="row">&nbsp;</td>
+         </tr>
+         <tr class="blueRow">
+            <td scope="row">1</td>
+            <td scope="row">FORM 4</td>
+            <td scope="row"><a href="/Archives/edgar/data/1000045/000135752118000008/edgar.xml">edgar.xml</a></td>
+            <td scope="row">4</td>
+            <td scope="row">4143</td>
+         </tr>
+         <tr>
+            <td scope="row">&nbsp;</td>
+            <td scope="row">Complete submission text file</td>
+            <td scope="row"><a href="/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008.txt">0001357521-18-000008.txt</a></td>
+            <td scope="row">&nbsp;</td>
+            <td scope="row">5549</td>
+         </tr>
+</table>
+</div>
+</div>
+</body>
+</html>
+`
+
+const sampleFilingIndexJSON = `{
+  "directory": {
+    "item": [
+      {"last-modified": "2018-10-15 17:09:53", "name": "0001357521-18-000008-index-headers.html", "type": "text.gif", "size": ""},
+      {"last-modified": "2018-10-15 17:09:53", "name": "0001357521-18-000008.txt", "type": "text.gif", "size": "5549"},
+      {"last-modified": "2018-10-15 17:09:53", "name": "edgar.xml", "type": "text.gif", "size": "4143"}
+    ],
+    "name": "/Archives/edgar/data/1000045/000135752118000008",
+    "parent-dir": "/Archives/edgar/data/1000045"
+  }
+}`
+
+func TestParseFilingIndex(t *testing.T) {
+	got, err := ParseFilingIndex(strings.NewReader(sampleFilingIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FilingDocument{
+		{
+			Sequence:    1,
+			Type:        "4",
+			Description: "FORM 4",
+			Filename:    "xslF345X03/edgar.xml",
+			URL:         "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/xslF345X03/edgar.xml",
+		},
+		{
+			Sequence:    1,
+			Type:        "4",
+			Description: "FORM 4",
+			Filename:    "edgar.xml",
+			Size:        4143,
+			URL:         "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/edgar.xml",
+		},
+		{
+			Description: "Complete submission text file",
+			Filename:    "0001357521-18-000008.txt",
+			Size:        5549,
+			URL:         "https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008.txt",
+		},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseFilingIndex(strings.NewReader("<html></html>")); err == nil {
+		t.Fatal("got no error for a page without a document table")
+	}
+}
+
+func TestClient_GetFilingDocuments(t *testing.T) {
+	indexHTM := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case indexHTM && r.URL.Path == "/edgar/data/1000045/000135752118000008/0001357521-18-000008-index.htm":
+			w.Write([]byte(sampleFilingIndex))
+		case r.URL.Path == "/edgar/data/1000045/000135752118000008/index.json":
+			w.Write([]byte(sampleFilingIndexJSON))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL))
+	a := AccessionNumber{FilerCIK: 1357521, Year: 18, Sequence: 8}
+	docs, err := c.GetFilingDocuments(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("got %d documents, want 3", len(docs))
+	}
+	if got, want := docs[1].URL, srv.URL+"/edgar/data/1000045/000135752118000008/edgar.xml"; got != want {
+		t.Fatalf("got URL %q, want %q", got, want)
+	}
+
+	// Fall back to index.json when the -index.htm page is missing.
+	indexHTM = false
+	docs, err = c.GetFilingDocuments(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FilingDocument{
+		{
+			Filename: "0001357521-18-000008-index-headers.html",
+			URL:      srv.URL + "/edgar/data/1000045/000135752118000008/0001357521-18-000008-index-headers.html",
+		},
+		{
+			Filename: "0001357521-18-000008.txt",
+			Size:     5549,
+			URL:      srv.URL + "/edgar/data/1000045/000135752118000008/0001357521-18-000008.txt",
+		},
+		{
+			Filename: "edgar.xml",
+			Size:     4143,
+			URL:      srv.URL + "/edgar/data/1000045/000135752118000008/edgar.xml",
+		},
+	}; !reflect.DeepEqual(docs, want) {
+		t.Fatalf("got %+v, want %+v", docs, want)
+	}
+
+	if _, err := c.GetFilingDocuments(context.Background(), 1000046, a); err == nil {
+		t.Fatal("got no error for a missing filing")
+	}
+}
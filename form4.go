@@ -15,44 +15,210 @@
 package sec
 
 import (
+	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
+	"iter"
 	"time"
 
-	"github.com/jadefox10200/httpext"
 	"github.com/jadefox10200/marshaler"
 )
 
-// A Form4 represents a SEC form 4 filing.
+// A Form4 represents a SEC form 4 filing. Its fields cover the
+// ownershipDocument elements of every published version of the EDGAR
+// Ownership XML Technical Specification, which form 3 and form 5 filings
+// share. Elements missing from a filing are left zero. The deprecated
+// ReportingOwner fields are set by ParseForm4 from the first reporting owner.
 type Form4 struct {
-	XMLName                         xml.Name           `xml:"ownershipDocument"`
-	PeriodOfReport                  marshaler.Date     `xml:"periodOfReport"`
-	IssuerCIK                       int                `xml:"issuer>issuerCik"`
-	IssuerName                      string             `xml:"issuer>issuerName"`
-	IssuerTradingSymbol             string             `xml:"issuer>issuerTradingSymbol"`
-	ReportingOwnerCIK               int                `xml:"reportingOwner>reportingOwnerId>rptOwnerCik"`
-	ReportingOwnerName              string             `xml:"reportingOwner>reportingOwnerId>rptOwnerName"`
-	ReportingOwnerTitle             string             `xml:"reportingOwner>reportingOwnerId>rptOwnerTitle"`
-	ReportingOwnerIsDirector        bool               `xml:"reportingOwner>reportingOwnerRelationship>isDirector"`
-	ReportingOwnerIsOfficer         bool               `xml:"reportingOwner>reportingOwnerRelationship>isOfficer"`
-	ReportingOwnerIsTenPercentOwner bool               `xml:"reportingOwner>reportingOwnerRelationship>isTenPercentOwner"`
-	NonDerivativeTransactions       []Form4Transaction `xml:"nonDerivativeTable>nonDerivativeTransaction"`
-	DeriviativeTransactions         []Form4Transaction `xml:"derivativeTable>derivativeTransaction"`
-}
-
-// A Form4Transaction represents a transaction in a SEC form 4 filing.
+	XMLName                   xml.Name              `xml:"ownershipDocument"`
+	SchemaVersion             string                `xml:"schemaVersion"`
+	DocumentType              string                `xml:"documentType"`
+	PeriodOfReport            marshaler.Date        `xml:"periodOfReport"`
+	DateOfOriginalSubmission  marshaler.Date        `xml:"dateOfOriginalSubmission"`
+	NoSecuritiesOwned         bool                  `xml:"noSecuritiesOwned"`
+	NotSubjectToSection16     bool                  `xml:"notSubjectToSection16"`
+	Form3HoldingsReported     bool                  `xml:"form3HoldingsReported"`
+	Form4TransactionsReported bool                  `xml:"form4TransactionsReported"`
+	Aff10b5One                bool                  `xml:"aff10b5One"`
+	IssuerCIK                 int                   `xml:"issuer>issuerCik"`
+	IssuerName                string                `xml:"issuer>issuerName"`
+	IssuerTradingSymbol       string                `xml:"issuer>issuerTradingSymbol"`
+	ReportingOwners           []Form4ReportingOwner `xml:"reportingOwner"`
+
+	// Deprecated: Use ReportingOwner().CIK.
+	ReportingOwnerCIK int `xml:"-"`
+	// Deprecated: Use ReportingOwner().Name.
+	ReportingOwnerName string `xml:"-"`
+	// Deprecated: Use ReportingOwner().OfficerTitle.
+	ReportingOwnerTitle string `xml:"-"`
+	// Deprecated: Use ReportingOwner().IsDirector.
+	ReportingOwnerIsDirector bool `xml:"-"`
+	// Deprecated: Use ReportingOwner().IsOfficer.
+	ReportingOwnerIsOfficer bool `xml:"-"`
+	// Deprecated: Use ReportingOwner().IsTenPercentOwner.
+	ReportingOwnerIsTenPercentOwner bool `xml:"-"`
+
+	NonDerivativeTransactions []Form4Transaction `xml:"nonDerivativeTable>nonDerivativeTransaction"`
+	NonDerivativeHoldings     []Form4Holding     `xml:"nonDerivativeTable>nonDerivativeHolding"`
+	DeriviativeTransactions   []Form4Transaction `xml:"derivativeTable>derivativeTransaction"`
+	DerivativeHoldings        []Form4Holding     `xml:"derivativeTable>derivativeHolding"`
+	Footnotes                 []Form4Footnote    `xml:"footnotes>footnote"`
+	Remarks                   string             `xml:"remarks"`
+	OwnerSignatures           []Form4Signature   `xml:"ownerSignature"`
+}
+
+// A Form4ReportingOwner represents a reporting owner of a SEC form 4 filing.
+// Joint filings, such as by a fund and its general partner, have several
+// reporting owners.
+type Form4ReportingOwner struct {
+	CIK               int          `xml:"reportingOwnerId>rptOwnerCik"`
+	Name              string       `xml:"reportingOwnerId>rptOwnerName"`
+	Address           Form4Address `xml:"reportingOwnerAddress"`
+	IsDirector        bool         `xml:"reportingOwnerRelationship>isDirector"`
+	IsOfficer         bool         `xml:"reportingOwnerRelationship>isOfficer"`
+	IsTenPercentOwner bool         `xml:"reportingOwnerRelationship>isTenPercentOwner"`
+	IsOther           bool         `xml:"reportingOwnerRelationship>isOther"`
+	OfficerTitle      string       `xml:"reportingOwnerRelationship>officerTitle"`
+	OtherText         string       `xml:"reportingOwnerRelationship>otherText"`
+}
+
+// ReportingOwner returns the first reporting owner of the filing, which is the
+// only reporting owner of most filings, or the zero value if there are none.
+func (f Form4) ReportingOwner() Form4ReportingOwner {
+	if len(f.ReportingOwners) == 0 {
+		return Form4ReportingOwner{}
+	}
+	return f.ReportingOwners[0]
+}
+
+// A Form4Address represents the address of a reporting owner in a SEC form 4
+// filing.
+type Form4Address struct {
+	Street1          string `xml:"rptOwnerStreet1"`
+	Street2          string `xml:"rptOwnerStreet2"`
+	City             string `xml:"rptOwnerCity"`
+	State            string `xml:"rptOwnerState"`
+	ZipCode          string `xml:"rptOwnerZipCode"`
+	StateDescription string `xml:"rptOwnerStateDescription"`
+}
+
+// A Form4Transaction represents a transaction in a SEC form 4 filing. The
+// conversion or exercise price, exercise and expiration dates and underlying
+// security are only reported for derivative transactions. Each value has the
+// IDs of its footnotes, which Form4.Footnote resolves.
 type Form4Transaction struct {
-	SecurityTitle                   string                  `xml:"securityTitle>value"`
-	Date                            marshaler.Date          `xml:"transactionDate>value"`
-	ConversionOrExercisePrice       marshaler.RobustFloat64 `xml:"conversionOrExercisePrice"`
-	FormType                        string                  `xml:"transactionCoding>transactionFormType"`
-	TransactionCode                 string                  `xml:"transactionCoding>transactionCode"`
-	EquitySwapInvolved              bool                    `xml:"transactionCoding>equitySwapInvolved"`
-	Shares                          float64                 `xml:"transactionAmounts>transactionShares>value"`
-	PricePerShare                   marshaler.RobustFloat64 `xml:"transactionAmounts>transactionPricePerShare>value"`
-	AcquiredDisposedCode            string                  `xml:"transactionAmounts>transactionAcquiredDisposedCode>value"`
-	SharesOwnedFollowingTransaction float64                 `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>value"`
-	DirectOrIndirectOwnership       string                  `xml:"ownershipNature>directOrIndirectOwnership>value"`
+	SecurityTitle                              string                  `xml:"securityTitle>value"`
+	SecurityTitleFootnoteIDs                   FootnoteIDs             `xml:"securityTitle>footnoteId"`
+	Date                                       marshaler.Date          `xml:"transactionDate>value"`
+	DateFootnoteIDs                            FootnoteIDs             `xml:"transactionDate>footnoteId"`
+	DeemedExecutionDate                        marshaler.Date          `xml:"deemedExecutionDate>value"`
+	DeemedExecutionDateFootnoteIDs             FootnoteIDs             `xml:"deemedExecutionDate>footnoteId"`
+	ConversionOrExercisePrice                  marshaler.RobustFloat64 `xml:"conversionOrExercisePrice>value"`
+	ConversionOrExercisePriceFootnoteIDs       FootnoteIDs             `xml:"conversionOrExercisePrice>footnoteId"`
+	FormType                                   string                  `xml:"transactionCoding>transactionFormType"`
+	TransactionCode                            string                  `xml:"transactionCoding>transactionCode"`
+	EquitySwapInvolved                         bool                    `xml:"transactionCoding>equitySwapInvolved"`
+	TransactionCodingFootnoteIDs               FootnoteIDs             `xml:"transactionCoding>footnoteId"`
+	Timeliness                                 string                  `xml:"transactionTimeliness>value"`
+	TimelinessFootnoteIDs                      FootnoteIDs             `xml:"transactionTimeliness>footnoteId"`
+	Shares                                     float64                 `xml:"transactionAmounts>transactionShares>value"`
+	SharesFootnoteIDs                          FootnoteIDs             `xml:"transactionAmounts>transactionShares>footnoteId"`
+	TotalValue                                 marshaler.RobustFloat64 `xml:"transactionAmounts>transactionTotalValue>value"`
+	TotalValueFootnoteIDs                      FootnoteIDs             `xml:"transactionAmounts>transactionTotalValue>footnoteId"`
+	PricePerShare                              marshaler.RobustFloat64 `xml:"transactionAmounts>transactionPricePerShare>value"`
+	PricePerShareFootnoteIDs                   FootnoteIDs             `xml:"transactionAmounts>transactionPricePerShare>footnoteId"`
+	AcquiredDisposedCode                       string                  `xml:"transactionAmounts>transactionAcquiredDisposedCode>value"`
+	AcquiredDisposedCodeFootnoteIDs            FootnoteIDs             `xml:"transactionAmounts>transactionAcquiredDisposedCode>footnoteId"`
+	ExerciseDate                               marshaler.Date          `xml:"exerciseDate>value"`
+	ExerciseDateFootnoteIDs                    FootnoteIDs             `xml:"exerciseDate>footnoteId"`
+	ExpirationDate                             marshaler.Date          `xml:"expirationDate>value"`
+	ExpirationDateFootnoteIDs                  FootnoteIDs             `xml:"expirationDate>footnoteId"`
+	UnderlyingSecurityTitle                    string                  `xml:"underlyingSecurity>underlyingSecurityTitle>value"`
+	UnderlyingSecurityTitleFootnoteIDs         FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityTitle>footnoteId"`
+	UnderlyingSecurityShares                   marshaler.RobustFloat64 `xml:"underlyingSecurity>underlyingSecurityShares>value"`
+	UnderlyingSecuritySharesFootnoteIDs        FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityShares>footnoteId"`
+	UnderlyingSecurityValue                    marshaler.RobustFloat64 `xml:"underlyingSecurity>underlyingSecurityValue>value"`
+	UnderlyingSecurityValueFootnoteIDs         FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityValue>footnoteId"`
+	SharesOwnedFollowingTransaction            float64                 `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>value"`
+	SharesOwnedFollowingTransactionFootnoteIDs FootnoteIDs             `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>footnoteId"`
+	ValueOwnedFollowingTransaction             marshaler.RobustFloat64 `xml:"postTransactionAmounts>valueOwnedFollowingTransaction>value"`
+	ValueOwnedFollowingTransactionFootnoteIDs  FootnoteIDs             `xml:"postTransactionAmounts>valueOwnedFollowingTransaction>footnoteId"`
+	DirectOrIndirectOwnership                  string                  `xml:"ownershipNature>directOrIndirectOwnership>value"`
+	DirectOrIndirectOwnershipFootnoteIDs       FootnoteIDs             `xml:"ownershipNature>directOrIndirectOwnership>footnoteId"`
+	NatureOfOwnership                          string                  `xml:"ownershipNature>natureOfOwnership>value"`
+	NatureOfOwnershipFootnoteIDs               FootnoteIDs             `xml:"ownershipNature>natureOfOwnership>footnoteId"`
+}
+
+// A Form4Holding represents a holding in a SEC form 4 filing. The conversion or
+// exercise price, exercise and expiration dates and underlying security are
+// only reported for derivative holdings. Each value has the IDs of its
+// footnotes, which Form4.Footnote resolves.
+type Form4Holding struct {
+	SecurityTitle                              string                  `xml:"securityTitle>value"`
+	SecurityTitleFootnoteIDs                   FootnoteIDs             `xml:"securityTitle>footnoteId"`
+	ConversionOrExercisePrice                  marshaler.RobustFloat64 `xml:"conversionOrExercisePrice>value"`
+	ConversionOrExercisePriceFootnoteIDs       FootnoteIDs             `xml:"conversionOrExercisePrice>footnoteId"`
+	FormType                                   string                  `xml:"transactionCoding>transactionFormType"`
+	TransactionCodingFootnoteIDs               FootnoteIDs             `xml:"transactionCoding>footnoteId"`
+	ExerciseDate                               marshaler.Date          `xml:"exerciseDate>value"`
+	ExerciseDateFootnoteIDs                    FootnoteIDs             `xml:"exerciseDate>footnoteId"`
+	ExpirationDate                             marshaler.Date          `xml:"expirationDate>value"`
+	ExpirationDateFootnoteIDs                  FootnoteIDs             `xml:"expirationDate>footnoteId"`
+	UnderlyingSecurityTitle                    string                  `xml:"underlyingSecurity>underlyingSecurityTitle>value"`
+	UnderlyingSecurityTitleFootnoteIDs         FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityTitle>footnoteId"`
+	UnderlyingSecurityShares                   marshaler.RobustFloat64 `xml:"underlyingSecurity>underlyingSecurityShares>value"`
+	UnderlyingSecuritySharesFootnoteIDs        FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityShares>footnoteId"`
+	UnderlyingSecurityValue                    marshaler.RobustFloat64 `xml:"underlyingSecurity>underlyingSecurityValue>value"`
+	UnderlyingSecurityValueFootnoteIDs         FootnoteIDs             `xml:"underlyingSecurity>underlyingSecurityValue>footnoteId"`
+	SharesOwnedFollowingTransaction            marshaler.RobustFloat64 `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>value"`
+	SharesOwnedFollowingTransactionFootnoteIDs FootnoteIDs             `xml:"postTransactionAmounts>sharesOwnedFollowingTransaction>footnoteId"`
+	ValueOwnedFollowingTransaction             marshaler.RobustFloat64 `xml:"postTransactionAmounts>valueOwnedFollowingTransaction>value"`
+	ValueOwnedFollowingTransactionFootnoteIDs  FootnoteIDs             `xml:"postTransactionAmounts>valueOwnedFollowingTransaction>footnoteId"`
+	DirectOrIndirectOwnership                  string                  `xml:"ownershipNature>directOrIndirectOwnership>value"`
+	DirectOrIndirectOwnershipFootnoteIDs       FootnoteIDs             `xml:"ownershipNature>directOrIndirectOwnership>footnoteId"`
+	NatureOfOwnership                          string                  `xml:"ownershipNature>natureOfOwnership>value"`
+	NatureOfOwnershipFootnoteIDs               FootnoteIDs             `xml:"ownershipNature>natureOfOwnership>footnoteId"`
+}
+
+// A Form4Footnote represents a footnote in a SEC form 4 filing, such as the
+// range of prices of a weighted average price, the adoption of a 10b5-1 plan or
+// the nature of an indirect ownership.
+type Form4Footnote struct {
+	ID   string `xml:"id,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Footnote returns the text of the footnote of the filing with the ID, if any.
+func (f Form4) Footnote(id string) (string, bool) {
+	for _, footnote := range f.Footnotes {
+		if footnote.ID == id {
+			return footnote.Text, true
+		}
+	}
+	return "", false
+}
+
+// FootnoteIDs are the IDs of the footnotes of a value in a SEC form 4 filing,
+// read from the id attributes of its footnoteId elements.
+type FootnoteIDs []string
+
+// UnmarshalXML implements the xml.Unmarshaler interface, appending the ID of
+// each footnoteId element.
+func (ids *FootnoteIDs) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "id" {
+			*ids = append(*ids, attr.Value)
+		}
+	}
+	return d.Skip()
+}
+
+// A Form4Signature represents the signature of an owner in a SEC form 4
+// filing.
+type Form4Signature struct {
+	Name string         `xml:"signatureName"`
+	Date marshaler.Date `xml:"signatureDate"`
 }
 
 // ParseForm4 parses a form 4 filing read from r.
@@ -61,6 +227,15 @@ func ParseForm4(r io.Reader) (*Form4, error) {
 	if err := xml.NewDecoder(r).Decode(&form); err != nil {
 		return nil, err
 	}
+
+	// Set the deprecated fields of the first reporting owner.
+	owner := form.ReportingOwner()
+	form.ReportingOwnerCIK = owner.CIK
+	form.ReportingOwnerName = owner.Name
+	form.ReportingOwnerTitle = owner.OfficerTitle
+	form.ReportingOwnerIsDirector = owner.IsDirector
+	form.ReportingOwnerIsOfficer = owner.IsOfficer
+	form.ReportingOwnerIsTenPercentOwner = owner.IsTenPercentOwner
 	return &form, nil
 }
 
@@ -82,44 +257,161 @@ func GetForm4Filings(start, end time.Time, f func(Form4) error) error {
 	return DefaultClient.GetForm4Filings(start, end, f)
 }
 
+// GetForm4FilingsContext is like GetForm4Filings but uses ctx for every request
+// and stops with ctx.Err() when ctx is done.
+//
+// GetForm4FilingsContext is a wrapper around
+// DefaultClient.GetForm4FilingsContext.
+func GetForm4FilingsContext(ctx context.Context, start, end time.Time, f func(Form4) error) error {
+	return DefaultClient.GetForm4FilingsContext(ctx, start, end, f)
+}
+
 // GetForm4Filings gets form 4 filings between start and end, calling f for each
 // filing. The end time will default to the current time when zero.
 func (c *Client) GetForm4Filings(start, end time.Time, f func(Form4) error) error {
+	return c.GetForm4FilingsContext(context.Background(), start, end, f)
+}
+
+// GetForm4FilingsContext is like GetForm4Filings but uses ctx for every request
+// and stops with ctx.Err() when ctx is done.
+//
+// Filings are fetched by the client's workers (see WithConcurrency) but f is
+// never called concurrently. Filings that fail to be fetched, extracted or
+// parsed are handled by the client's FilingErrorHandler. Filings it skips or
+// collects are summarized in the FilingErrors returned once all other filings
+// were processed.
+func (c *Client) GetForm4FilingsContext(ctx context.Context, start, end time.Time, f func(Form4) error) error {
 	// Use DefaultClient when nil.
 	if c == nil {
 		c = DefaultClient
 	}
 
-	return c.GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
-		// Skip all forms except form 4 filings and amended form 4 filings.
-		if e.FormType != FormType4 && e.FormType != FormType4A {
-			return nil
+	var errs FilingErrors
+	q := IndexQuery{Start: start, End: end}
+	if err := c.form4s(ctx, q, func(form *Form4, err error) error {
+		if err != nil {
+			filingErr, ok := err.(*FilingError)
+			if !ok {
+				return err
+			}
+			switch action := c.handleFilingError(filingErr); action {
+			case FilingErrorSkip, FilingErrorCollect:
+				errs.add(filingErr, action)
+				return nil
+			}
+			return filingErr
 		}
+		return f(*form)
+	}); err != nil {
+		return err
+	}
 
-		// Send an HTTP request.
-		url := e.URL()
-		resp, err := c.client.Get(url)
-		if err != nil {
-			return nil
+	if errs.Len() > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Form4s returns an iterator over the form 4 filings of the EDGAR index
+// entries selected by q.
+//
+// Form4s is a wrapper around DefaultClient.Form4s.
+func Form4s(ctx context.Context, q IndexQuery) iter.Seq2[Form4, error] {
+	return DefaultClient.Form4s(ctx, q)
+}
+
+// Form4s returns an iterator over the form 4 filings of the EDGAR index entries
+// selected by q. Filings are fetched as the loop advances, and all requests are
+// closed when the loop breaks. The form types of q default to form 4 filings
+// and amended form 4 filings, and any other form type is an error.
+//
+// Filings that fail to be fetched, extracted or parsed are yielded as a
+// *FilingError, and the iteration continues unless the loop breaks. Any other
+// error ends the iteration.
+func (c *Client) Form4s(ctx context.Context, q IndexQuery) iter.Seq2[Form4, error] {
+	return func(yield func(Form4, error) bool) {
+		// Use DefaultClient when nil.
+		if c == nil {
+			c = DefaultClient
 		}
-		if httpext.IsErrorStatus(resp.StatusCode) {
-			resp.Body.Close()
-			return httpext.StatusError{URL: url, StatusCode: resp.StatusCode}
+
+		// Never yield again once the loop broke, whatever the error.
+		stopped := false
+		if err := c.form4s(ctx, q, func(form *Form4, err error) error {
+			if err != nil {
+				if _, ok := err.(*FilingError); !ok {
+					return err
+				}
+				if !yield(Form4{}, err) {
+					stopped = true
+					return errStopIteration
+				}
+				return nil
+			}
+			if !yield(*form, nil) {
+				stopped = true
+				return errStopIteration
+			}
+			return nil
+		}); err != nil && !stopped {
+			yield(Form4{}, err)
 		}
+	}
+}
 
-		// Parse the form 4 filing from the SEC document.
-		form, err := ParseForm4FromSECDocument(resp.Body)
-		if err != nil {
-			resp.Body.Close()
-			return err
+// form4s gets the form 4 filings selected by q, calling deliver for each
+// filing or *FilingError. Filings are fetched by the client's workers but
+// deliver is never called concurrently.
+func (c *Client) form4s(ctx context.Context, q IndexQuery, deliver func(*Form4, error) error) error {
+	// Skip all forms except form 4 filings and amended form 4 filings by
+	// default, and refuse to parse other forms as form 4 filings.
+	if len(q.FormTypes) == 0 {
+		q.FormTypes = []string{FormType4}
+		q.IncludeAmendments = true
+	}
+	for _, formType := range q.FormTypes {
+		if formType != FormType4 && formType != FormType4A {
+			return fmt.Errorf("sec.Form4s: unsupported form type %q", formType)
 		}
+	}
+	entries := func(f func(EDGARIndexEntry) error) error {
+		return c.QueryEDGARIndexEntries(ctx, q, f)
+	}
 
-		// Call f with the filing.
-		if err := f(*form); err != nil {
-			resp.Body.Close()
-			return err
+	// Stop with ctx.Err() rather than delivering cancelled filings.
+	deliverContext := func(form *Form4, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
 		}
+		return deliver(form, err)
+	}
 
-		return resp.Body.Close()
+	if c.concurrency > 1 {
+		return c.getForm4sConcurrently(ctx, entries, deliverContext)
+	}
+	return entries(func(e EDGARIndexEntry) error {
+		return deliverContext(c.getForm4(ctx, e))
 	})
 }
+
+// getForm4 gets the form 4 filing of e, returning a *FilingError when it fails.
+func (c *Client) getForm4(ctx context.Context, e EDGARIndexEntry) (*Form4, error) {
+	// Send an HTTP request.
+	url := c.EDGARIndexEntryURL(e)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return nil, &FilingError{Entry: e, URL: url, Stage: FilingStageFetch, Err: err}
+	}
+	defer resp.Body.Close()
+
+	// Parse the form 4 filing from the SEC document.
+	r, err := ExtractTagFromSECDocument(resp.Body, "XML")
+	if err != nil {
+		return nil, &FilingError{Entry: e, URL: url, Stage: FilingStageExtract, Err: err}
+	}
+	form, err := ParseForm4(r)
+	if err != nil {
+		return nil, &FilingError{Entry: e, URL: url, Stage: FilingStageParse, Err: err}
+	}
+	return form, nil
+}
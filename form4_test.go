@@ -15,8 +15,11 @@
 package sec
 
 import (
+	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/xml"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -367,13 +370,31 @@ ISSUER:
 </SEC-DOCUMENT>`
 
 var sampleForm4 = &Form4{
-	XMLName:                 xml.Name{Local: "ownershipDocument"},
-	PeriodOfReport:          marshaler.Date(time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)),
-	IssuerCIK:               1000045,
-	IssuerName:              "NICHOLAS FINANCIAL INC",
-	IssuerTradingSymbol:     "NICK",
+	XMLName:             xml.Name{Local: "ownershipDocument"},
+	SchemaVersion:       "X0306",
+	DocumentType:        "4",
+	PeriodOfReport:      marshaler.Date(time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)),
+	IssuerCIK:           1000045,
+	IssuerName:          "NICHOLAS FINANCIAL INC",
+	IssuerTradingSymbol: "NICK",
+	ReportingOwners: []Form4ReportingOwner{
+		{
+			CIK:  1357521,
+			Name: "MALSON KELLY M",
+			Address: Form4Address{
+				Street1: "2454 MCMULLEN BOOTH ROAD",
+				Street2: "BUILDING C",
+				City:    "CLEARWATER",
+				State:   "FL",
+				ZipCode: "33759",
+			},
+			IsOfficer:    true,
+			OfficerTitle: "CFO",
+		},
+	},
 	ReportingOwnerCIK:       1357521,
 	ReportingOwnerName:      "MALSON KELLY M",
+	ReportingOwnerTitle:     "CFO",
 	ReportingOwnerIsOfficer: true,
 	NonDerivativeTransactions: []Form4Transaction{
 		Form4Transaction{
@@ -383,7 +404,9 @@ var sampleForm4 = &Form4{
 			FormType:                        "4",
 			TransactionCode:                 "P",
 			Shares:                          1569,
+			SharesFootnoteIDs:               FootnoteIDs{"F1"},
 			PricePerShare:                   11.980000,
+			PricePerShareFootnoteIDs:        FootnoteIDs{"F2"},
 			AcquiredDisposedCode:            "A",
 			SharesOwnedFollowingTransaction: 15989,
 			DirectOrIndirectOwnership:       "D",
@@ -394,13 +417,26 @@ var sampleForm4 = &Form4{
 			ConversionOrExercisePrice:       0.000000,
 			FormType:                        "4",
 			TransactionCode:                 "A",
+			TransactionCodingFootnoteIDs:    FootnoteIDs{"F3"},
 			Shares:                          1569,
 			PricePerShare:                   0.000000,
+			PricePerShareFootnoteIDs:        FootnoteIDs{"F3"},
 			AcquiredDisposedCode:            "A",
 			SharesOwnedFollowingTransaction: 17558,
 			DirectOrIndirectOwnership:       "D",
 		},
 	},
+	Footnotes: []Form4Footnote{
+		{ID: "F1", Text: "Purchases of shares was made in accordance with a 10b5-1 Plan previously executed."},
+		{ID: "F2", Text: "Represents the average purchase price."},
+		{ID: "F3", Text: "These shares were awarded pursuant to the reporting person's employment agreement.  The closing stock price of the issuer's common stock on NASDAQ on 10/15/2018 was $11.79."},
+	},
+	OwnerSignatures: []Form4Signature{
+		{
+			Name: "/s/ Kelly M. Malson",
+			Date: marshaler.Date(time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)),
+		},
+	},
 }
 
 func TestParseForm4Filing(t *testing.T) {
@@ -413,6 +449,197 @@ func TestParseForm4Filing(t *testing.T) {
 	}
 }
 
+func TestForm4_ReportingOwners(t *testing.T) {
+	got, err := ParseForm4(strings.NewReader(`<?xml version="1.0"?>
+<ownershipDocument>
+    <reportingOwner>
+        <reportingOwnerId>
+            <rptOwnerCik>0001000001</rptOwnerCik>
+            <rptOwnerName>SAMPLE CAPITAL FUND LP</rptOwnerName>
+        </reportingOwnerId>
+        <reportingOwnerRelationship>
+            <isTenPercentOwner>1</isTenPercentOwner>
+        </reportingOwnerRelationship>
+    </reportingOwner>
+    <reportingOwner>
+        <reportingOwnerId>
+            <rptOwnerCik>0001000002</rptOwnerCik>
+            <rptOwnerName>SAMPLE CAPITAL GP LLC</rptOwnerName>
+        </reportingOwnerId>
+        <reportingOwnerRelationship>
+            <isTenPercentOwner>1</isTenPercentOwner>
+            <isOther>1</isOther>
+            <otherText>General Partner</otherText>
+        </reportingOwnerRelationship>
+    </reportingOwner>
+</ownershipDocument>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Form4ReportingOwner{
+		{CIK: 1000001, Name: "SAMPLE CAPITAL FUND LP", IsTenPercentOwner: true},
+		{CIK: 1000002, Name: "SAMPLE CAPITAL GP LLC", IsTenPercentOwner: true, IsOther: true, OtherText: "General Partner"},
+	}; !reflect.DeepEqual(got.ReportingOwners, want) {
+		t.Fatalf("got %+v, want %+v", got.ReportingOwners, want)
+	}
+	if owner := got.ReportingOwner(); owner.CIK != 1000001 {
+		t.Fatalf("got reporting owner %+v, want the first", owner)
+	}
+	if got.ReportingOwnerCIK != 1000001 || got.ReportingOwnerName != "SAMPLE CAPITAL FUND LP" || !got.ReportingOwnerIsTenPercentOwner {
+		t.Fatalf("got deprecated reporting owner fields %d, %q, %v, want the first reporting owner", got.ReportingOwnerCIK, got.ReportingOwnerName, got.ReportingOwnerIsTenPercentOwner)
+	}
+	if owner := (Form4{}).ReportingOwner(); owner != (Form4ReportingOwner{}) {
+		t.Fatalf("got reporting owner %+v, want none", owner)
+	}
+}
+
+func TestForm4_Footnote(t *testing.T) {
+	got, ok := sampleForm4.Footnote("F2")
+	if want := "Represents the average purchase price."; !ok || got != want {
+		t.Fatalf("got %q, %v, want %q, true", got, ok, want)
+	}
+	if got, ok := sampleForm4.Footnote("F4"); ok {
+		t.Fatalf("got %q for an unknown footnote", got)
+	}
+}
+
+func TestParseForm4Derivatives(t *testing.T) {
+	got, err := ParseForm4(strings.NewReader(`<?xml version="1.0"?>
+<ownershipDocument>
+    <schemaVersion>X0508</schemaVersion>
+    <documentType>4/A</documentType>
+    <periodOfReport>2023-04-03</periodOfReport>
+    <dateOfOriginalSubmission>2023-04-05</dateOfOriginalSubmission>
+    <notSubjectToSection16>0</notSubjectToSection16>
+    <aff10b5One>1</aff10b5One>
+    <derivativeTable>
+        <derivativeTransaction>
+            <securityTitle><value>Stock Option (Right to Buy)</value></securityTitle>
+            <conversionOrExercisePrice><value>25.50</value></conversionOrExercisePrice>
+            <transactionDate><value>2023-04-03</value></transactionDate>
+            <transactionCoding>
+                <transactionFormType>4</transactionFormType>
+                <transactionCode>M</transactionCode>
+                <equitySwapInvolved>0</equitySwapInvolved>
+            </transactionCoding>
+            <transactionAmounts>
+                <transactionShares><value>1000</value></transactionShares>
+                <transactionPricePerShare><value>0</value></transactionPricePerShare>
+                <transactionAcquiredDisposedCode><value>D</value></transactionAcquiredDisposedCode>
+            </transactionAmounts>
+            <exerciseDate><value>2021-01-15</value></exerciseDate>
+            <expirationDate><value>2030-01-15</value></expirationDate>
+            <underlyingSecurity>
+                <underlyingSecurityTitle><value>Common Stock</value></underlyingSecurityTitle>
+                <underlyingSecurityShares><value>1000</value></underlyingSecurityShares>
+            </underlyingSecurity>
+            <postTransactionAmounts>
+                <sharesOwnedFollowingTransaction><value>4000</value></sharesOwnedFollowingTransaction>
+            </postTransactionAmounts>
+            <ownershipNature>
+                <directOrIndirectOwnership><value>D</value></directOrIndirectOwnership>
+            </ownershipNature>
+        </derivativeTransaction>
+        <derivativeHolding>
+            <securityTitle><value>Restricted Stock Units</value></securityTitle>
+            <conversionOrExercisePrice><footnoteId id="F1"/></conversionOrExercisePrice>
+            <exerciseDate><footnoteId id="F2"/></exerciseDate>
+            <expirationDate><footnoteId id="F2"/></expirationDate>
+            <underlyingSecurity>
+                <underlyingSecurityTitle><value>Common Stock</value></underlyingSecurityTitle>
+                <underlyingSecurityShares><value>500</value></underlyingSecurityShares>
+            </underlyingSecurity>
+            <postTransactionAmounts>
+                <sharesOwnedFollowingTransaction><value>500</value></sharesOwnedFollowingTransaction>
+            </postTransactionAmounts>
+            <ownershipNature>
+                <directOrIndirectOwnership><value>I</value></directOrIndirectOwnership>
+                <natureOfOwnership><value>By Trust</value></natureOfOwnership>
+            </ownershipNature>
+        </derivativeHolding>
+    </derivativeTable>
+    <remarks>Amended to correct the exercise price.</remarks>
+</ownershipDocument>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != "X0508" || got.DocumentType != "4/A" || !got.Aff10b5One || got.Remarks != "Amended to correct the exercise price." {
+		t.Fatalf("got %+v", got)
+	}
+	if want := marshaler.Date(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)); got.DateOfOriginalSubmission != want {
+		t.Fatalf("got date of original submission %v, want %v", got.DateOfOriginalSubmission, want)
+	}
+	if want := []Form4Transaction{
+		{
+			SecurityTitle:                   "Stock Option (Right to Buy)",
+			Date:                            marshaler.Date(time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC)),
+			ConversionOrExercisePrice:       25.5,
+			FormType:                        "4",
+			TransactionCode:                 "M",
+			Shares:                          1000,
+			AcquiredDisposedCode:            "D",
+			ExerciseDate:                    marshaler.Date(time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)),
+			ExpirationDate:                  marshaler.Date(time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)),
+			UnderlyingSecurityTitle:         "Common Stock",
+			UnderlyingSecurityShares:        1000,
+			SharesOwnedFollowingTransaction: 4000,
+			DirectOrIndirectOwnership:       "D",
+		},
+	}; !reflect.DeepEqual(got.DeriviativeTransactions, want) {
+		t.Fatalf("got %+v, want %+v", got.DeriviativeTransactions, want)
+	}
+	if want := []Form4Holding{
+		{
+			SecurityTitle:                        "Restricted Stock Units",
+			ConversionOrExercisePriceFootnoteIDs: FootnoteIDs{"F1"},
+			ExerciseDateFootnoteIDs:              FootnoteIDs{"F2"},
+			ExpirationDateFootnoteIDs:            FootnoteIDs{"F2"},
+			UnderlyingSecurityTitle:              "Common Stock",
+			UnderlyingSecurityShares:             500,
+			SharesOwnedFollowingTransaction:      500,
+			DirectOrIndirectOwnership:            "I",
+			NatureOfOwnership:                    "By Trust",
+		},
+	}; !reflect.DeepEqual(got.DerivativeHoldings, want) {
+		t.Fatalf("got %+v, want %+v", got.DerivativeHoldings, want)
+	}
+}
+
+// TestParseForm4ValuePaths covers values that were read from the wrong
+// elements: the conversion or exercise price from its wrapper element instead
+// of its value, and the officer title from rptOwnerTitle, which is not part of
+// the schema, instead of officerTitle.
+func TestParseForm4ValuePaths(t *testing.T) {
+	got, err := ParseForm4(strings.NewReader(`<?xml version="1.0"?>
+<ownershipDocument>
+    <reportingOwner>
+        <reportingOwnerId>
+            <rptOwnerCik>0001357521</rptOwnerCik>
+        </reportingOwnerId>
+        <reportingOwnerRelationship>
+            <isOfficer>1</isOfficer>
+            <officerTitle>CFO</officerTitle>
+        </reportingOwnerRelationship>
+    </reportingOwner>
+    <derivativeTable>
+        <derivativeTransaction>
+            <conversionOrExercisePrice>
+                <value>25.50</value>
+            </conversionOrExercisePrice>
+        </derivativeTransaction>
+    </derivativeTable>
+</ownershipDocument>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got.DeriviativeTransactions[0].ConversionOrExercisePrice, marshaler.RobustFloat64(25.50); got != want {
+		t.Fatalf("got conversion or exercise price %v, want %v", got, want)
+	}
+	if got, want := got.ReportingOwner().OfficerTitle, "CFO"; got != want {
+		t.Fatalf("got officer title %q, want %q", got, want)
+	}
+}
+
 func TestParseForm4FilingFromSECDocument(t *testing.T) {
 	got, err := ParseForm4FromSECDocument(strings.NewReader(sampleForm4SECDocument))
 	if err != nil {
@@ -445,7 +672,7 @@ func TestClient_GetForm4Filings(t *testing.T) {
 			res.Body = r
 		}
 		return &res, nil
-	}))
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
 	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
 	got := []Form4{}
@@ -459,3 +686,137 @@ func TestClient_GetForm4Filings(t *testing.T) {
 		t.Fatalf("got %+v, want %+v", got, want)
 	}
 }
+
+func TestClient_Form4s(t *testing.T) {
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		if strings.Contains(req.URL.Path, "edgar/data") {
+			res.Body = ioutil.NopCloser(strings.NewReader(sampleForm4SECDocument))
+		} else {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(sampleEDGARIndex))
+			gz.Close()
+			res.Body = ioutil.NopCloser(&buf)
+		}
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
+	q := IndexQuery{
+		Start: time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+	got := []Form4{}
+	for form, err := range c.Form4s(context.Background(), q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, form)
+	}
+	if want := []Form4{*sampleForm4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_Form4sCancelBreak(t *testing.T) {
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		if strings.Contains(req.URL.Path, "edgar/data") {
+			res.Body = ioutil.NopCloser(strings.NewReader(sampleForm4SECDocument))
+		} else {
+			res.Body = ioutil.NopCloser(strings.NewReader(sampleEDGARIndex))
+		}
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever))
+	q := IndexQuery{
+		Start: time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Breaking after cancelling the context must end the iteration without
+	// yielding the context error.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	for _, err := range c.Form4s(ctx, q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+		cancel()
+		break
+	}
+	if n != 1 {
+		t.Fatalf("got %d filings, want 1", n)
+	}
+}
+
+func TestClient_Form4sFormTypes(t *testing.T) {
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s", req.URL)
+		return nil, nil
+	}), WithUserAgent(testUserAgent))
+	q := IndexQuery{FormTypes: []string{FormType4, "8-K"}}
+	n := 0
+	for _, err := range c.Form4s(context.Background(), q) {
+		if n++; err == nil {
+			t.Fatal("got no error for a form type other than form 4")
+		}
+	}
+	if n != 1 {
+		t.Fatalf("got %d results, want 1", n)
+	}
+}
+
+func TestClient_GetForm4FilingsFilingErrorHandler(t *testing.T) {
+	transport := httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		if strings.Contains(req.URL.Path, "edgar/data") {
+			res.StatusCode = http.StatusNotFound
+			res.Body = ioutil.NopCloser(strings.NewReader(""))
+		} else {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(sampleEDGARIndex))
+			gz.Close()
+			res.Body = ioutil.NopCloser(&buf)
+		}
+		return &res, nil
+	})
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		action FilingErrorAction
+		check  func(error) bool
+	}{
+		{FilingErrorAbort, func(err error) bool {
+			var filingErr *FilingError
+			return errors.As(err, &filingErr) && filingErr.Stage == FilingStageFetch
+		}},
+		{FilingErrorSkip, func(err error) bool {
+			errs, ok := err.(FilingErrors)
+			return ok && errs.Len() == 1 && len(errs.Errors) == 0 &&
+				reflect.DeepEqual(errs.Skipped, map[FilingStage]int{FilingStageFetch: 1})
+		}},
+		{FilingErrorCollect, func(err error) bool {
+			errs, ok := err.(FilingErrors)
+			return ok && errs.Len() == 1 && errs.Errors[0].Entry.CIK == 1000045 &&
+				reflect.DeepEqual(errs.Stages(), map[FilingStage]int{FilingStageFetch: 1})
+		}},
+	} {
+		var handled []*FilingError
+		c := NewClient(transport, WithUserAgent(testUserAgent), WithDailyIndex(DailyIndexNever), WithFilingErrorHandler(func(err *FilingError) FilingErrorAction {
+			handled = append(handled, err)
+			return test.action
+		}))
+		err := c.GetForm4Filings(start, end, func(form Form4) error {
+			t.Fatal("unexpected filing")
+			return nil
+		})
+		if !test.check(err) {
+			t.Errorf("action %d: unexpected error %v", test.action, err)
+		}
+		if len(handled) != 1 || handled[0].URL != "https://www.sec.gov/Archives/edgar/data/1000045/0001357521-18-000008.txt" {
+			t.Errorf("action %d: got handled errors %v", test.action, handled)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// An IndexQuery selects EDGAR index entries. Its zero value selects all
+// entries up to the current time.
+type IndexQuery struct {
+	// Start and End select entries filed between them, inclusive. End will
+	// default to the current time when zero.
+	Start, End time.Time
+
+	// FormTypes selects entries of the form types when not empty.
+	FormTypes []string
+
+	// IncludeAmendments is whether FormTypes also selects the amended form
+	// types, e.g. "4/A" for "4".
+	IncludeAmendments bool
+
+	// CIKs selects entries of the CIKs when not empty.
+	CIKs []int
+
+	// CompanyName and Filename select entries whose company name and filename
+	// match them when not nil.
+	CompanyName *regexp.Regexp
+	Filename    *regexp.Regexp
+}
+
+// Match returns whether q selects e, ignoring the date range.
+func (q IndexQuery) Match(e EDGARIndexEntry) bool {
+	return q.matcher()(e)
+}
+
+// matcher returns a function returning whether q selects an entry, ignoring
+// the date range.
+func (q IndexQuery) matcher() func(EDGARIndexEntry) bool {
+	var formTypes map[string]bool
+	if len(q.FormTypes) > 0 {
+		formTypes = make(map[string]bool)
+		for _, formType := range q.FormTypes {
+			formTypes[formType] = true
+			if q.IncludeAmendments && !IsFormAmended(formType) {
+				formTypes[formType+"/A"] = true
+			}
+		}
+	}
+
+	var ciks map[int]bool
+	if len(q.CIKs) > 0 {
+		ciks = make(map[int]bool)
+		for _, cik := range q.CIKs {
+			ciks[cik] = true
+		}
+	}
+
+	return func(e EDGARIndexEntry) bool {
+		switch {
+		case formTypes != nil && !formTypes[e.FormType]:
+			return false
+		case ciks != nil && !ciks[e.CIK]:
+			return false
+		case q.CompanyName != nil && !q.CompanyName.MatchString(e.CompanyName):
+			return false
+		case q.Filename != nil && !q.Filename.MatchString(e.Filename):
+			return false
+		}
+		return true
+	}
+}
+
+// errStopIteration stops walking the EDGAR index when an iterator loop breaks.
+var errStopIteration = errors.New("sec: stop iteration")
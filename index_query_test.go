@@ -0,0 +1,48 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestIndexQuery_Match(t *testing.T) {
+	e := EDGARIndexEntry{
+		CIK:         1000045,
+		CompanyName: "NICHOLAS FINANCIAL INC",
+		FormType:    "4/A",
+		Filename:    "edgar/data/1000045/0001357521-18-000008.txt",
+	}
+	for _, test := range []struct {
+		q    IndexQuery
+		want bool
+	}{
+		{IndexQuery{}, true},
+		{IndexQuery{FormTypes: []string{"4"}}, false},
+		{IndexQuery{FormTypes: []string{"4"}, IncludeAmendments: true}, true},
+		{IndexQuery{FormTypes: []string{"3", "4/A"}}, true},
+		{IndexQuery{CIKs: []int{1000045}}, true},
+		{IndexQuery{CIKs: []int{1000184}}, false},
+		{IndexQuery{CompanyName: regexp.MustCompile(`(?i)financial`)}, true},
+		{IndexQuery{CompanyName: regexp.MustCompile(`^SAP`)}, false},
+		{IndexQuery{Filename: regexp.MustCompile(`/0001357521-`)}, true},
+		{IndexQuery{CIKs: []int{1000045}, Filename: regexp.MustCompile(`\.htm$`)}, false},
+	} {
+		if got := test.q.Match(e); got != test.want {
+			t.Errorf("%+v: got %v, want %v", test.q, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBrowseEDGARURL is the default URL of EDGAR company browsing, which
+// serves the latest filings feed.
+const DefaultBrowseEDGARURL = "https://www.sec.gov/cgi-bin/browse-edgar"
+
+// DefaultPollInterval is the interval between polls of the latest filings feed
+// used by PollLatestFilings when none is given.
+const DefaultPollInterval = 30 * time.Second
+
+// latestFilingsCount is the number of filings requested per page of the
+// latest filings feed, which is the most it serves.
+const latestFilingsCount = 100
+
+// WithBrowseEDGARURL sets the URL of EDGAR company browsing, e.g. a local
+// stand-in serving the latest filings feed.
+func WithBrowseEDGARURL(url string) ClientOption {
+	return func(c *Client) {
+		c.browseEDGARURL = url
+	}
+}
+
+// atomFeed is the Atom feed of the latest filings.
+type atomFeed struct {
+	Entries []struct {
+		Title   string `xml:"title"`
+		Summary string `xml:"summary"`
+		Updated string `xml:"updated"`
+		ID      string `xml:"id"`
+		Link    struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Category struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+var (
+	// latestFilingTitle matches titles like "4 - MALSON KELLY M (0001357521)
+	// (Reporting)".
+	latestFilingTitle = regexp.MustCompile(`^(.+?) - (.*) \((\d+)\) \(([^)]*)\)$`)
+
+	// latestFilingFiled matches the date filed in summaries.
+	latestFilingFiled = regexp.MustCompile(`Filed:(?:</b>)?\s*(\d{4}-\d{2}-\d{2})`)
+)
+
+// ParseLatestFilingsFeed parses the Atom feed of the latest EDGAR filings read
+// from r, returning its entries newest first. A filing is listed once for each
+// of its filers, such as the reporting owner and issuer of a form 4 filing.
+//
+// See: https://www.sec.gov/cgi-bin/browse-edgar?action=getcurrent
+func ParseLatestFilingsFeed(r io.Reader) ([]EDGARIndexEntry, error) {
+	filings, err := parseLatestFilingsFeed(r)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]EDGARIndexEntry, len(filings))
+	for i, l := range filings {
+		entries[i] = l.entry
+	}
+	return entries, nil
+}
+
+// A latestFiling is a listing of a filing in the latest filings feed.
+type latestFiling struct {
+	entry           EDGARIndexEntry
+	accessionNumber AccessionNumber
+
+	// updated is the time the filing was listed.
+	updated time.Time
+}
+
+// A latestListing identifies a listing in the latest filings feed, which lists
+// a filing once for each of its filers.
+type latestListing struct {
+	accessionNumber AccessionNumber
+	cik             int
+}
+
+// listing returns the listing of l.
+func (l latestFiling) listing() latestListing {
+	return latestListing{l.accessionNumber, l.entry.CIK}
+}
+
+// parseLatestFilingsFeed parses the Atom feed of the latest EDGAR filings read
+// from r, returning its listings newest first.
+func parseLatestFilingsFeed(r io.Reader) ([]latestFiling, error) {
+	var feed atomFeed
+	d := xml.NewDecoder(r)
+	d.CharsetReader = latin1Reader
+	if err := d.Decode(&feed); err != nil {
+		return nil, err
+	}
+
+	filings := make([]latestFiling, 0, len(feed.Entries))
+	for _, fe := range feed.Entries {
+		m := latestFilingTitle.FindStringSubmatch(strings.TrimSpace(fe.Title))
+		if m == nil {
+			return nil, fmt.Errorf("sec.ParseLatestFilingsFeed: unexpected title %q", fe.Title)
+		}
+		cik, err := strconv.Atoi(m[3])
+		if err != nil {
+			return nil, err
+		}
+
+		formType := fe.Category.Term
+		if formType == "" {
+			formType = m[1]
+		}
+
+		_, id, ok := strings.Cut(fe.ID, "accession-number=")
+		if !ok {
+			return nil, fmt.Errorf("sec.ParseLatestFilingsFeed: unexpected id %q", fe.ID)
+		}
+		accessionNumber, err := ParseAccessionNumber(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fall back to the date of the last update when the summary lacks
+		// the date filed.
+		updated := strings.TrimSpace(fe.Updated)
+		dateFiled := updated
+		if m := latestFilingFiled.FindStringSubmatch(fe.Summary); m != nil {
+			dateFiled = m[1]
+		}
+		if len(dateFiled) < len("2006-01-02") {
+			return nil, fmt.Errorf("sec.ParseLatestFilingsFeed: unexpected date %q", dateFiled)
+		}
+		date, err := time.Parse("2006-01-02", dateFiled[:len("2006-01-02")])
+		if err != nil {
+			return nil, err
+		}
+
+		// Order filings without an update time by their date filed.
+		updatedTime, err := time.Parse(time.RFC3339, updated)
+		if err != nil {
+			updatedTime = date
+		}
+
+		filings = append(filings, latestFiling{
+			entry: EDGARIndexEntry{
+				CIK:         cik,
+				CompanyName: m[2],
+				FormType:    formType,
+				DateFiled:   date,
+				Filename:    accessionNumber.SubmissionPath(cik),
+			},
+			accessionNumber: accessionNumber,
+			updated:         updatedTime,
+		})
+	}
+	return filings, nil
+}
+
+// latin1Reader is an xml.Decoder.CharsetReader for ISO-8859-1, which the
+// latest filings feed declares.
+func latin1Reader(charset string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+	default:
+		return nil, fmt.Errorf("sec.latin1Reader: unsupported charset %q", charset)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return strings.NewReader(string(runes)), nil
+}
+
+// GetLatestFilings gets the latest EDGAR filings of formType, or of all form
+// types when empty, newest first.
+//
+// GetLatestFilings is a wrapper around DefaultClient.GetLatestFilings.
+func GetLatestFilings(ctx context.Context, formType string) ([]EDGARIndexEntry, error) {
+	return DefaultClient.GetLatestFilings(ctx, formType)
+}
+
+// PollLatestFilings polls the latest EDGAR filings every interval until ctx is
+// done, calling f for each new filing selected by q, oldest first.
+//
+// PollLatestFilings is a wrapper around DefaultClient.PollLatestFilings.
+func PollLatestFilings(ctx context.Context, q IndexQuery, interval time.Duration, f func(EDGARIndexEntry) error) error {
+	return DefaultClient.PollLatestFilings(ctx, q, interval, f)
+}
+
+// GetLatestFilings gets the latest EDGAR filings of formType, or of all form
+// types when empty, newest first. EDGAR matches form types by prefix, e.g. "4"
+// also matches "4/A" and "40-F".
+func (c *Client) GetLatestFilings(ctx context.Context, formType string) ([]EDGARIndexEntry, error) {
+	// Use DefaultClient when nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	filings, err := c.getLatestFilings(ctx, formType, 0)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]EDGARIndexEntry, len(filings))
+	for i, l := range filings {
+		entries[i] = l.entry
+	}
+	return entries, nil
+}
+
+// getLatestFilings gets the page of the latest EDGAR filings of formType
+// starting at the listing start, newest first.
+func (c *Client) getLatestFilings(ctx context.Context, formType string, start int) ([]latestFiling, error) {
+	params := url.Values{
+		"action": {"getcurrent"},
+		"type":   {formType},
+		"owner":  {"include"},
+		"start":  {strconv.Itoa(start)},
+		"count":  {strconv.Itoa(latestFilingsCount)},
+		"output": {"atom"},
+	}
+	resp, err := c.get(ctx, c.browseEDGARURL+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parseLatestFilingsFeed(resp.Body)
+}
+
+// ErrLatestFilingsGap is returned by PollLatestFilings when more filings were
+// listed between two polls than the latest filings feed holds, so that some
+// of them were missed.
+var ErrLatestFilingsGap = errors.New("sec: filings missed between polls of the latest filings feed")
+
+// PollLatestFilings polls the latest EDGAR filings every interval until ctx is
+// done, calling f for each new filing selected by q, oldest first. The date
+// range of q is ignored. A filing is selected when any of its listings, such as
+// the listings of the reporting owner and issuer of a form 4 filing, is
+// selected, and it is only returned for the first of them. The interval
+// defaults to DefaultPollInterval when zero.
+//
+// The first poll returns the filings on the first page of the feed. Later
+// polls page through the feed back to the filings of the previous poll, and
+// return ErrLatestFilingsGap after the filings found when the feed ends first.
+// PollLatestFilings returns ctx.Err() when ctx is done, or the first error of
+// a poll or f.
+func (c *Client) PollLatestFilings(ctx context.Context, q IndexQuery, interval time.Duration, f func(EDGARIndexEntry) error) error {
+	// Use DefaultClient when nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	// Request each form type separately, or all form types at once.
+	formTypes := q.FormTypes
+	if len(formTypes) == 0 {
+		formTypes = []string{""}
+	}
+
+	// Remember the listings seen and the filings returned with the time they
+	// were listed, forgetting them once they are older than every listing of
+	// a poll, as listings only leave the feed from its end.
+	match := q.matcher()
+	listed := make(map[latestListing]time.Time)
+	returned := make(map[AccessionNumber]time.Time)
+	polled := make(map[string]bool)
+	for {
+		var filings []latestFiling
+		gap := false
+		for _, formType := range formTypes {
+			n := 0
+			for start := 0; ; start += latestFilingsCount {
+				page, err := c.getLatestFilings(ctx, formType, start)
+				if err != nil {
+					return err
+				}
+				filings = append(filings, page...)
+				n += len(page)
+				if !polled[formType] || slices.ContainsFunc(page, func(l latestFiling) bool {
+					_, ok := listed[l.listing()]
+					return ok
+				}) {
+					break
+				}
+				if len(page) < latestFilingsCount {
+					gap = true
+					break
+				}
+			}
+			polled[formType] = polled[formType] || n > 0
+		}
+
+		// Merge the feeds of the form types newest first, keeping the order
+		// of the feed for listings of the same time.
+		slices.SortStableFunc(filings, func(a, b latestFiling) int {
+			return b.updated.Compare(a.updated)
+		})
+
+		var entries []EDGARIndexEntry
+		for _, l := range filings {
+			listed[l.listing()] = l.updated
+			if _, ok := returned[l.accessionNumber]; ok || !match(l.entry) {
+				continue
+			}
+			returned[l.accessionNumber] = l.updated
+			entries = append(entries, l.entry)
+		}
+		if len(filings) > 0 {
+			oldest := filings[len(filings)-1].updated
+			maps.DeleteFunc(listed, func(_ latestListing, t time.Time) bool {
+				return t.Before(oldest)
+			})
+			maps.DeleteFunc(returned, func(_ AccessionNumber, t time.Time) bool {
+				return t.Before(oldest)
+			})
+		}
+
+		// Return the oldest filings first.
+		slices.Reverse(entries)
+		for _, e := range entries {
+			if err := f(e); err != nil {
+				return err
+			}
+		}
+		if gap {
+			return ErrLatestFilingsGap
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
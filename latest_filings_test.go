@@ -0,0 +1,249 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const sampleLatestFilingsFeedEntry = `<entry>
+<title>4 - MALSON KELLY M (0001357521) (Reporting)</title>
+<link rel="alternate" type="text/html" href="https://www.sec.gov/Archives/edgar/data/1357521/000135752118000008/0001357521-18-000008-index.htm"/>
+<summary type="html"> &lt;b&gt;Filed:&lt;/b&gt; 2018-10-15 &lt;b&gt;AccNo:&lt;/b&gt; 0001357521-18-000008 &lt;b&gt;Size:&lt;/b&gt; 5 KB</summary>
+<updated>2018-10-15T17:12:43-04:00</updated>
+<category scheme="https://www.sec.gov/" label="form type" term="4"/>
+<id>urn:tag:sec.gov,2008:accession-number=0001357521-18-000008</id>
+</entry>
+<entry>
+<title>4 - NICHOLAS FINANCIAL INC (0001000045) (Issuer)</title>
+<link rel="alternate" type="text/html" href="https://www.sec.gov/Archives/edgar/data/1000045/000135752118000008/0001357521-18-000008-index.htm"/>
+<summary type="html"> &lt;b&gt;Filed:&lt;/b&gt; 2018-10-15 &lt;b&gt;AccNo:&lt;/b&gt; 0001357521-18-000008 &lt;b&gt;Size:&lt;/b&gt; 5 KB</summary>
+<updated>2018-10-15T17:12:43-04:00</updated>
+<category scheme="https://www.sec.gov/" label="form type" term="4"/>
+<id>urn:tag:sec.gov,2008:accession-number=0001357521-18-000008</id>
+</entry>`
+
+const sampleNewLatestFilingsFeedEntry = `<entry>
+<title>8-K - SAP SE (0001000184) (Filer)</title>
+<link rel="alternate" type="text/html" href="https://www.sec.gov/Archives/edgar/data/1000184/000110465918062851/0001104659-18-062851-index.htm"/>
+<summary type="html"> &lt;b&gt;Filed:&lt;/b&gt; 2018-10-16 &lt;b&gt;AccNo:&lt;/b&gt; 0001104659-18-062851 &lt;b&gt;Size:&lt;/b&gt; 1 MB</summary>
+<updated>2018-10-16T09:00:00-04:00</updated>
+<category scheme="https://www.sec.gov/" label="form type" term="8-K"/>
+<id>urn:tag:sec.gov,2008:accession-number=0001104659-18-062851</id>
+</entry>`
+
+func sampleLatestFilingsFeed(entries ...string) string {
+	return `<?xml version="1.0" encoding="ISO-8859-1" ?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title>Latest Filings - Mon, 15 Oct 2018 17:20:00 EDT</title>
+` + strings.Join(entries, "\n") + `
+</feed>`
+}
+
+func TestParseLatestFilingsFeed(t *testing.T) {
+	got, err := ParseLatestFilingsFeed(strings.NewReader(sampleLatestFilingsFeed(sampleLatestFilingsFeedEntry)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dateFiled := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	if want := []EDGARIndexEntry{
+		EDGARIndexEntry{
+			CIK:         1357521,
+			CompanyName: "MALSON KELLY M",
+			FormType:    "4",
+			DateFiled:   dateFiled,
+			Filename:    "edgar/data/1357521/0001357521-18-000008.txt",
+		},
+		EDGARIndexEntry{
+			CIK:         1000045,
+			CompanyName: "NICHOLAS FINANCIAL INC",
+			FormType:    "4",
+			DateFiled:   dateFiled,
+			Filename:    "edgar/data/1000045/0001357521-18-000008.txt",
+		},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_PollLatestFilings(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("action"); got != "getcurrent" {
+			t.Errorf("got action %q, want getcurrent", got)
+		}
+		polls++
+		if polls == 1 {
+			w.Write([]byte(sampleLatestFilingsFeed(sampleLatestFilingsFeedEntry)))
+			return
+		}
+		w.Write([]byte(sampleLatestFilingsFeed(sampleNewLatestFilingsFeedEntry, sampleLatestFilingsFeedEntry)))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithBrowseEDGARURL(srv.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := []string{}
+	err := c.PollLatestFilings(ctx, IndexQuery{}, time.Millisecond, func(e EDGARIndexEntry) error {
+		got = append(got, e.CompanyName)
+		if len(got) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if want := []string{"MALSON KELLY M", "SAP SE"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// latestFilingsFeedEntry returns a feed entry of filing i of formType filed by
+// CIK 1000000+i, listed i minutes after 9:00.
+func latestFilingsFeedEntry(i int, formType string) string {
+	return fmt.Sprintf(`<entry>
+<title>%[2]s - COMPANY %[1]d (%010[3]d) (Filer)</title>
+<updated>%[4]s</updated>
+<category scheme="https://www.sec.gov/" label="form type" term="%[2]s"/>
+<id>urn:tag:sec.gov,2008:accession-number=0001104659-18-%06[1]d</id>
+</entry>`, i, formType, 1000000+i, time.Date(2018, 10, 16, 9, 0, 0, 0, time.UTC).Add(time.Duration(i)*time.Minute).Format(time.RFC3339))
+}
+
+// pollLatestFilings polls a feed of at most size entries for q, returning the
+// CIKs of the filings and the error once polls polls were made. The feed lists
+// the entries returned by feed for each poll and form type, newest first.
+func pollLatestFilings(t *testing.T, q IndexQuery, size, polls int, feed func(poll int, formType string) []string) ([]int, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	counts := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		formType := r.URL.Query().Get("type")
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		if start == 0 {
+			counts[formType]++
+		}
+		if counts[formType] > polls {
+			cancel()
+			return
+		}
+		entries := feed(counts[formType], formType)
+		entries = entries[:min(len(entries), size)]
+		entries = entries[min(start, len(entries)):min(start+count, len(entries))]
+		w.Write([]byte(sampleLatestFilingsFeed(entries...)))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithBrowseEDGARURL(srv.URL), WithRateLimit(rate.Inf, 1))
+	got := []int{}
+	err := c.PollLatestFilings(ctx, q, time.Millisecond, func(e EDGARIndexEntry) error {
+		got = append(got, e.CIK)
+		return nil
+	})
+	return got, err
+}
+
+// latestFilingsRange returns the feed entries of filings from to to, newest
+// first, and their CIKs, oldest first.
+func latestFilingsRange(from, to int, formType string) ([]string, []int) {
+	var entries []string
+	var ciks []int
+	for i := to; i >= from; i-- {
+		entries = append(entries, latestFilingsFeedEntry(i, formType))
+		ciks = append([]int{1000000 + i}, ciks...)
+	}
+	return entries, ciks
+}
+
+func TestClient_PollLatestFilingsIssuer(t *testing.T) {
+	got, err := pollLatestFilings(t, IndexQuery{CIKs: []int{1000045}}, 100, 2, func(int, string) []string {
+		return []string{sampleLatestFilingsFeedEntry}
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if want := []int{1000045}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClient_PollLatestFilingsPages(t *testing.T) {
+	first, want := latestFilingsRange(1, 1, "4")
+	next, nextCIKs := latestFilingsRange(2, 151, "4")
+	want = append(want, nextCIKs...)
+	got, err := pollLatestFilings(t, IndexQuery{}, 1000, 2, func(poll int, _ string) []string {
+		if poll == 1 {
+			return first
+		}
+		return append(next, first...)
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClient_PollLatestFilingsGap(t *testing.T) {
+	// The feed only holds the newest 200 of the 250 new filings.
+	first, want := latestFilingsRange(1, 1, "4")
+	next, _ := latestFilingsRange(2, 251, "4")
+	_, listed := latestFilingsRange(52, 251, "4")
+	want = append(want, listed...)
+	got, err := pollLatestFilings(t, IndexQuery{}, 200, 2, func(poll int, _ string) []string {
+		if poll == 1 {
+			return first
+		}
+		return append(next, first...)
+	})
+	if err != ErrLatestFilingsGap {
+		t.Fatalf("got error %v, want %v", err, ErrLatestFilingsGap)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestClient_PollLatestFilingsFormTypes(t *testing.T) {
+	got, err := pollLatestFilings(t, IndexQuery{FormTypes: []string{"4", "8-K"}}, 100, 1, func(_ int, formType string) []string {
+		if formType == "4" {
+			return []string{latestFilingsFeedEntry(3, "4"), latestFilingsFeedEntry(1, "4")}
+		}
+		return []string{latestFilingsFeedEntry(4, "8-K"), latestFilingsFeedEntry(2, "8-K")}
+	})
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if want := []int{1000001, 1000002, 1000003, 1000004}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
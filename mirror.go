@@ -0,0 +1,185 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jadefox10200/httpext"
+)
+
+// A Mirror is a local copy of the EDGAR full index and daily index, stored in a
+// directory with the layout of the EDGAR archives, e.g.
+// "edgar/full-index/2018/QTR4/master.gz".
+type Mirror struct {
+	client *Client
+	dir    string
+}
+
+// NewMirror returns a Mirror stored in dir of the index files read by c. The
+// client will default to DefaultClient when nil.
+func NewMirror(c *Client, dir string) *Mirror {
+	if c == nil {
+		c = DefaultClient
+	}
+	return &Mirror{client: c, dir: dir}
+}
+
+// Dir returns the directory of the mirror.
+func (m *Mirror) Dir() string {
+	return m.dir
+}
+
+// Sync downloads the index files the mirror's client reads between start and
+// end into the mirror. The end time will default to the current time when
+// zero.
+//
+// Files that no longer change, such as the full index of a past quarter, are
+// only downloaded when missing. Other files, such as the full index of the
+// current quarter, are refreshed with conditional requests and only downloaded
+// again when modified.
+func (m *Mirror) Sync(ctx context.Context, start, end time.Time) error {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	for _, file := range m.client.edgarIndexFiles(m.client.indexType, start, end) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := m.syncFile(ctx, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncFile downloads file into the mirror unless it is up to date. The
+// modification time of the local file is set to the Last-Modified time of the
+// response.
+func (m *Mirror) syncFile(ctx context.Context, file edgarIndexFile) error {
+	name := filepath.Join(m.dir, filepath.FromSlash(file.path))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.url, nil)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) && file.fallback != nil {
+		// Keep syncing the fallback when it is what the mirror has.
+		if _, fallbackErr := os.Stat(filepath.Join(m.dir, filepath.FromSlash(file.fallback.path))); fallbackErr == nil {
+			return m.syncFile(ctx, *file.fallback)
+		}
+	}
+	switch {
+	case err == nil:
+		// Skip files last modified after they stopped changing.
+		if !info.ModTime().Before(file.final) {
+			return nil
+		}
+		req.Header.Set("If-Modified-Since", info.ModTime().UTC().Format(http.TimeFormat))
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	resp, err := m.client.send(req)
+	if err != nil {
+		if statusErr, ok := err.(httpext.StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+			if file.fallback != nil {
+				return m.syncFile(ctx, *file.fallback)
+			}
+			if file.optional {
+				return nil
+			}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	modTime, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		modTime = time.Now()
+	}
+
+	// Write to a temporary file first so that an interrupted download never
+	// leaves a partial file in the mirror.
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), modTime, modTime); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Client returns a copy of the mirror's client that reads the full index and
+// daily index from the mirror, without network access. Index files missing
+// from the mirror fail like missing files on EDGAR, so Sync should cover every
+// range queried. Filings are still requested from the EDGAR archives.
+func (m *Mirror) Client() *Client {
+	c := *m.client
+	c.client = &http.Client{
+		Transport: mirrorTransport{
+			file: http.NewFileTransport(http.Dir(m.dir)),
+			next: m.client.client.Transport,
+		},
+		CheckRedirect: m.client.client.CheckRedirect,
+		Jar:           m.client.client.Jar,
+		Timeout:       m.client.client.Timeout,
+	}
+	c.fullIndexRoot = "file:///edgar/full-index/"
+	c.dailyIndexRoot = "file:///edgar/daily-index/"
+	return &c
+}
+
+// mirrorTransport serves file URLs from a mirror and sends other requests
+// with the next transport.
+type mirrorTransport struct {
+	file http.RoundTripper
+	next http.RoundTripper
+}
+
+func (t mirrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "file" {
+		return t.file.RoundTrip(req)
+	}
+	if t.next == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMirror(t *testing.T) {
+	gzipped := func(s string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(s))
+		gz.Close()
+		return buf.Bytes()
+	}
+	files := map[string][]byte{
+		"/edgar/full-index/2018/QTR3/master.gz": gzipped(sampleEDGARIndex[:strings.Index(sampleEDGARIndex, "1000045|")]),
+		"/edgar/full-index/2018/QTR4/master.gz": gzipped(sampleEDGARIndex),
+	}
+	modTimes := map[string]time.Time{
+		"/edgar/full-index/2018/QTR3/master.gz": time.Date(2018, 10, 2, 6, 0, 0, 0, time.UTC),
+		"/edgar/full-index/2018/QTR4/master.gz": time.Date(2018, 10, 20, 6, 0, 0, 0, time.UTC),
+	}
+
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		request := r.URL.Path
+		if r.Header.Get("If-Modified-Since") != "" {
+			request += " (conditional)"
+		}
+		requests = append(requests, request)
+		b, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "", modTimes[r.URL.Path], bytes.NewReader(b))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL), WithDailyIndex(DailyIndexNever))
+	m := NewMirror(c, dir)
+	start := time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	syncMirror := func(want ...string) {
+		t.Helper()
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+		if err := m.Sync(context.Background(), start, end); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(requests, want) {
+			t.Fatalf("got requests %q, want %q", requests, want)
+		}
+	}
+
+	// The first sync downloads every file.
+	syncMirror("/edgar/full-index/2018/QTR4/master.gz", "/edgar/full-index/2018/QTR3/master.gz")
+	info, err := os.Stat(filepath.Join(dir, "edgar", "full-index", "2018", "QTR4", "master.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.ModTime().UTC(), modTimes["/edgar/full-index/2018/QTR4/master.gz"]; !got.Equal(want) {
+		t.Fatalf("got modification time %v, want %v", got, want)
+	}
+
+	// The fourth quarter may still change, so it is refreshed with a
+	// conditional request while the third quarter is skipped.
+	syncMirror("/edgar/full-index/2018/QTR4/master.gz (conditional)")
+
+	// Once modified after the quarter, the file no longer changes.
+	modTimes["/edgar/full-index/2018/QTR4/master.gz"] = time.Date(2019, 1, 2, 6, 0, 0, 0, time.UTC)
+	syncMirror("/edgar/full-index/2018/QTR4/master.gz (conditional)")
+	syncMirror()
+
+	// The mirror's client reads the index without network access.
+	srv.Close()
+	got := []EDGARIndexEntry{}
+	if err := m.Client().GetEDGARIndexEntries(start, end, func(e EDGARIndexEntry) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].CIK != 1000045 || got[1].CIK != 1000184 {
+		t.Fatalf("got %+v, want the entries of the sample index", got)
+	}
+
+	// Ranges that were not synced fail like missing files on EDGAR.
+	if err := m.Client().GetEDGARIndexEntries(start.AddDate(-1, 0, 0), start.AddDate(-1, 0, 0), func(EDGARIndexEntry) error {
+		return nil
+	}); err == nil {
+		t.Fatal("got no error for a missing index file")
+	}
+}
+
+func TestMirrorDailyIndexGzipped(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(strings.Replace(sampleDailyEDGARIndex, "20181015", "20181016", 1)))
+	gz.Close()
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path != "/edgar/daily-index/2018/QTR4/master.20181016.idx.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "", time.Date(2018, 10, 17, 6, 0, 0, 0, time.UTC), bytes.NewReader(buf.Bytes()))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL), WithDailyIndex(DailyIndexAlways))
+	m := NewMirror(c, t.TempDir())
+	day := time.Date(2018, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	// Days only published gzipped are downloaded once.
+	for _, want := range [][]string{
+		{"/edgar/daily-index/2018/QTR4/master.20181016.idx", "/edgar/daily-index/2018/QTR4/master.20181016.idx.gz"},
+		nil,
+	} {
+		requests = nil
+		if err := m.Sync(context.Background(), day, day); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(requests, want) {
+			t.Fatalf("got requests %q, want %q", requests, want)
+		}
+	}
+
+	srv.Close()
+	got := []EDGARIndexEntry{}
+	if err := m.Client().GetEDGARIndexEntries(day, day, func(e EDGARIndexEntry) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || !got[0].DateFiled.Equal(day) {
+		t.Fatalf("got %+v, want the entry of the daily index", got)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"sync"
+)
+
+// WithConcurrency sets the number of workers fetching filings. Workers share
+// the client's rate limit. A concurrency of 1 or less fetches filings
+// sequentially, which is the default.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// WithOrderedDelivery sets whether filings fetched by concurrent workers are
+// delivered in index order rather than as soon as they are fetched.
+func WithOrderedDelivery(ordered bool) ClientOption {
+	return func(c *Client) {
+		c.ordered = ordered
+	}
+}
+
+// A form4Job is a form 4 filing to fetch.
+type form4Job struct {
+	seq   int
+	entry EDGARIndexEntry
+}
+
+// A form4Result is a fetched form 4 filing.
+type form4Result struct {
+	seq  int
+	form *Form4
+	err  error
+}
+
+// getForm4sConcurrently fetches the form 4 filings of the entries walked by
+// entries with the client's workers, calling deliver for each result from the
+// calling goroutine.
+func (c *Client) getForm4sConcurrently(ctx context.Context, entries func(func(EDGARIndexEntry) error) error, deliver func(*Form4, error) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Bound the number of filings in flight so that ordered delivery cannot
+	// buffer unboundedly behind a slow filing.
+	window := make(chan struct{}, 2*c.concurrency)
+	jobs := make(chan form4Job)
+	results := make(chan form4Result)
+
+	// Walk the entries. The walker's error is buffered so that it can be read
+	// once the walker is done, even when nothing is left to deliver.
+	entriesErr := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		seq := 0
+		entriesErr <- entries(func(e EDGARIndexEntry) error {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case jobs <- form4Job{seq, e}:
+				seq++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	// Fetch the filings.
+	var wg sync.WaitGroup
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				form, err := c.getForm4(ctx, job.entry)
+				select {
+				case results <- form4Result{job.seq, form, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Deliver the filings, draining the results after an error.
+	var err error
+	next := 0
+	pending := make(map[int]form4Result)
+	for r := range results {
+		if err != nil {
+			continue
+		}
+		if !c.ordered {
+			<-window
+			if err = deliver(r.form, r.err); err != nil {
+				cancel()
+			}
+			continue
+		}
+		pending[r.seq] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-window
+			if err = deliver(r.form, r.err); err != nil {
+				cancel()
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// Wait for the walker, as the workers stop without draining the jobs
+	// once the context is done.
+	walkErr := <-entriesErr
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return walkErr
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jadefox10200/httpext"
+	"golang.org/x/time/rate"
+)
+
+func TestClient_GetForm4FilingsConcurrently(t *testing.T) {
+	const n = 20
+	index := sampleEDGARIndex[:strings.Index(sampleEDGARIndex, "1000045|")]
+	want := []int{}
+	for i := 1; i <= n; i++ {
+		index += fmt.Sprintf("%d|ISSUER %d|4|2018-10-15|edgar/data/%d/0001357521-18-%06d.txt\n", i, i, i, i)
+		want = append(want, i)
+	}
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	transport := httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		if !strings.Contains(req.URL.Path, "edgar/data") {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(index))
+			gz.Close()
+			res.Body = io.NopCloser(&buf)
+			return &res, nil
+		}
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		// Answer later filings sooner.
+		i, err := strconv.Atoi(strings.Split(req.URL.Path, "/")[4])
+		if err != nil {
+			return nil, err
+		}
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+		doc := strings.Replace(sampleForm4SECDocument, "<issuerCik>0001000045</issuerCik>", fmt.Sprintf("<issuerCik>%d</issuerCik>", i), 1)
+		res.Body = io.NopCloser(strings.NewReader(doc))
+		return &res, nil
+	})
+
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	for _, ordered := range []bool{false, true} {
+		maxInFlight = 0
+		c := NewClient(transport,
+			WithUserAgent(testUserAgent),
+			WithDailyIndex(DailyIndexNever),
+			WithRateLimit(rate.Inf, 1),
+			WithConcurrency(4),
+			WithOrderedDelivery(ordered))
+		got := []int{}
+		if err := c.GetForm4Filings(start, end, func(form Form4) error {
+			got = append(got, form.IssuerCIK)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if maxInFlight > 4 {
+			t.Errorf("got %d filings in flight, want at most 4", maxInFlight)
+		}
+		if len(got) != n {
+			t.Fatalf("got %d filings, want %d", len(got), n)
+		}
+		if ordered && !reflect.DeepEqual(got, want) {
+			t.Fatalf("got filings %v, want %v", got, want)
+		}
+	}
+}
+
+func TestClient_GetForm4FilingsConcurrentlyCancelled(t *testing.T) {
+	index := sampleEDGARIndex[:strings.Index(sampleEDGARIndex, "1000045|")]
+	for i := 1; i <= 50; i++ {
+		index += fmt.Sprintf("%d|ISSUER %d|4|2018-10-15|edgar/data/%d/0001357521-18-%06d.txt\n", i, i, i, i)
+	}
+
+	transport := httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		var res http.Response
+		if !strings.Contains(req.URL.Path, "edgar/data") {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(index))
+			gz.Close()
+			res.Body = io.NopCloser(&buf)
+			return &res, nil
+		}
+		res.Body = io.NopCloser(strings.NewReader(sampleForm4SECDocument))
+		return &res, nil
+	})
+
+	start := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2018, 10, 19, 0, 0, 0, 0, time.UTC)
+	for _, ordered := range []bool{false, true} {
+		c := NewClient(transport,
+			WithUserAgent(testUserAgent),
+			WithDailyIndex(DailyIndexNever),
+			WithRateLimit(rate.Inf, 1),
+			WithConcurrency(4),
+			WithOrderedDelivery(ordered))
+		ctx, cancel := context.WithCancel(context.Background())
+		n := 0
+		err := c.GetForm4FilingsContext(ctx, start, end, func(form Form4) error {
+			// Cancel the context from outside, leaving the filing handler
+			// to return nil.
+			if n++; n == 3 {
+				cancel()
+			}
+			return nil
+		})
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v, want %v", err, context.Canceled)
+		}
+	}
+}
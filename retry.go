@@ -0,0 +1,171 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jadefox10200/httpext"
+)
+
+// ErrRequestRateThresholdExceeded is returned when the SEC keeps serving its
+// "Request Rate Threshold Exceeded" page after all retries.
+var ErrRequestRateThresholdExceeded = errors.New("sec: request rate threshold exceeded")
+
+// requestRateThresholdExceeded is the marker of the SEC throttle page, which is
+// served with 403 Forbidden.
+var requestRateThresholdExceeded = []byte("Request Rate Threshold Exceeded")
+
+// maxErrorBodySize is the maximum number of bytes read from an error response
+// when looking for the SEC throttle page.
+const maxErrorBodySize = 64 << 10
+
+// A RetryPolicy configures how a Client retries requests that fail with a
+// network error, 429 Too Many Requests, a 5xx status or the SEC throttle page,
+// and reads of EDGAR index files that fail partway through.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request, including
+	// the first. Values below 1 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the backoff before the first retry. The backoff doubles
+	// with each retry up to MaxBackoff, and is jittered to between half and
+	// all of its value. A Retry-After header takes precedence, but is
+	// capped at MaxBackoff so that a server cannot stall the client.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WithRetryPolicy sets the retry policy of a Client.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// backoff returns the jittered backoff before retrying after attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// send sends req, retrying according to the client's retry policy. It returns
+// an httpext.StatusError, or an error wrapping ErrRequestRateThresholdExceeded,
+// when the final response has an error status.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.do(req.Clone(ctx))
+		if err == nil && !httpext.IsErrorStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		var wait time.Duration
+		retry := false
+		if err != nil {
+			// Only retry network errors, not errors such as a missing
+			// User-Agent or a cancelled context.
+			var urlErr *url.Error
+			retry = errors.As(err, &urlErr) && ctx.Err() == nil
+		} else {
+			wait, _ = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			retry, err = checkErrorResponse(req.URL.String(), resp)
+		}
+		if !retry || attempt >= c.retryPolicy.MaxAttempts {
+			return nil, err
+		}
+
+		if wait == 0 {
+			wait = c.retryPolicy.backoff(attempt)
+		}
+		if maxBackoff := c.retryPolicy.MaxBackoff; maxBackoff > 0 && wait > maxBackoff {
+			wait = maxBackoff
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// checkErrorResponse closes resp, which has an error status, returning whether
+// the request should be retried and the error describing the response.
+func checkErrorResponse(url string, resp *http.Response) (bool, error) {
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusForbidden:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if bytes.Contains(body, requestRateThresholdExceeded) {
+			return true, fmt.Errorf("%w: %s", ErrRequestRateThresholdExceeded, url)
+		}
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true, httpext.StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	return false, httpext.StatusError{URL: url, StatusCode: resp.StatusCode}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date, relative to now.
+func parseRetryAfter(s string, now time.Time) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadefox10200/httpext"
+)
+
+const sampleThrottlePage = `<html><head><title>SEC.gov | Request Rate Threshold Exceeded</title></head></html>`
+
+func TestClient_Retry(t *testing.T) {
+	responses := []struct {
+		statusCode int
+		body       string
+	}{
+		{http.StatusServiceUnavailable, ""},
+		{http.StatusForbidden, sampleThrottlePage},
+		{http.StatusOK, "ok"},
+	}
+	attempts := 0
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		r := responses[attempts]
+		attempts++
+		var res http.Response
+		res.StatusCode = r.statusCode
+		res.Body = io.NopCloser(strings.NewReader(r.body))
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	}))
+
+	resp, err := c.get(context.Background(), "https://www.sec.gov/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Fatalf("got body %q, want %q", body, "ok")
+	}
+	if attempts != 3 {
+		t.Fatalf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestClient_RetryAfterCapped(t *testing.T) {
+	attempts := 0
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		attempts++
+		var res http.Response
+		res.StatusCode = http.StatusOK
+		if attempts == 1 {
+			res.StatusCode = http.StatusTooManyRequests
+			res.Header = http.Header{"Retry-After": {"86400"}}
+		}
+		res.Body = io.NopCloser(strings.NewReader(""))
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := c.get(ctx, "https://www.sec.gov/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts != 2 {
+		t.Fatalf("got %d attempts, want 2", attempts)
+	}
+}
+
+func TestClient_RetryThrottled(t *testing.T) {
+	attempts := 0
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		attempts++
+		var res http.Response
+		res.StatusCode = http.StatusForbidden
+		res.Body = io.NopCloser(strings.NewReader(sampleThrottlePage))
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+
+	if _, err := c.get(context.Background(), "https://www.sec.gov/"); !errors.Is(err, ErrRequestRateThresholdExceeded) {
+		t.Fatalf("got error %v, want %v", err, ErrRequestRateThresholdExceeded)
+	}
+	if attempts != 2 {
+		t.Fatalf("got %d attempts, want 2", attempts)
+	}
+}
+
+func TestClient_RetryNotFound(t *testing.T) {
+	attempts := 0
+	c := NewClient(httpext.WithTransportFunc(nil, func(req *http.Request) (*http.Response, error) {
+		attempts++
+		var res http.Response
+		res.StatusCode = http.StatusNotFound
+		res.Body = io.NopCloser(strings.NewReader(""))
+		return &res, nil
+	}), WithUserAgent(testUserAgent), WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	_, err := c.get(context.Background(), "https://www.sec.gov/")
+	if want := (httpext.StatusError{URL: "https://www.sec.gov/", StatusCode: http.StatusNotFound}); err != want {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+	if attempts != 1 {
+		t.Fatalf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		s    string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Mon, 15 Oct 2018 00:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 14 Oct 2018 23:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(test.s, now)
+		if got != test.want || ok != test.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", test.s, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/fs"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// A SubmissionDocument is a document of an EDGAR submission.
+type SubmissionDocument struct {
+	Type        string
+	Sequence    int
+	Filename    string
+	Description string
+
+	// Text reads the content of the TEXT tag of the document like
+	// ExtractTagFromSECDocument. It is only valid until the iteration
+	// advances.
+	Text io.Reader
+
+	// Binary is whether the content of the document is uuencoded, such as
+	// for PDF, image, Excel and ZIP exhibits. Text then reads the decoded
+	// content, and BinaryFilename and BinaryMode are the original filename
+	// and mode of the content.
+	Binary         bool
+	BinaryFilename string
+	BinaryMode     fs.FileMode
+
+	// text reads the raw content of the TEXT tag.
+	text io.Reader
+}
+
+// ParseSubmissionDocuments returns an iterator over the documents of an EDGAR
+// submission read from r, such as a complete submission text file. Documents
+// are read as the loop advances, skipping any content of the previous document
+// left unread. Uuencoded content is decoded transparently.
+func ParseSubmissionDocuments(r io.Reader) iter.Seq2[*SubmissionDocument, error] {
+	return func(yield func(*SubmissionDocument, error) bool) {
+		br, ok := r.(*bufio.Reader)
+		if !ok {
+			br = bufio.NewReader(r)
+		}
+		for {
+			doc, err := readSubmissionDocument(br)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(doc, nil) {
+				return
+			}
+			if _, err := io.Copy(io.Discard, doc.text); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// readSubmissionDocument reads the next document from br up to its text,
+// returning io.EOF when there are no more documents.
+func readSubmissionDocument(br *bufio.Reader) (*SubmissionDocument, error) {
+	var doc *SubmissionDocument
+	for {
+		// The text may share a line with its tag.
+		if doc != nil {
+			b, _ := br.Peek(maxTagSize)
+			start := skipSpace(b, 0)
+			if size, ok := matchTag(b[start:], "TEXT", false); ok {
+				br.Discard(start + size)
+				doc.text = newTagFromSECDocumentReader(br, "TEXT")
+				text := bufio.NewReader(doc.text)
+				doc.Text = text
+				if name, mode, ok := detectUuencoded(text); ok {
+					doc.Binary, doc.BinaryFilename, doc.BinaryMode = true, name, mode
+					doc.Text = &uudecoder{br: text}
+				}
+				return doc, nil
+			}
+		}
+
+		line, err := br.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF && doc != nil {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		tag, value, ok := parseSGMLTag(line)
+		if !ok {
+			continue
+		}
+		if doc == nil {
+			if tag == "DOCUMENT" {
+				doc = &SubmissionDocument{}
+			}
+			continue
+		}
+		switch tag {
+		case "TYPE":
+			doc.Type = value
+		case "SEQUENCE":
+			if doc.Sequence, err = strconv.Atoi(value); err != nil {
+				return nil, err
+			}
+		case "FILENAME":
+			doc.Filename = value
+		case "DESCRIPTION":
+			doc.Description = value
+		}
+	}
+}
+
+// parseSGMLTag parses a line like "<TYPE>4" as an upper case tag and its
+// value.
+func parseSGMLTag(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "<") {
+		return "", "", false
+	}
+	tag, value, ok := strings.Cut(line[1:], ">")
+	if !ok {
+		return "", "", false
+	}
+	return strings.ToUpper(strings.TrimSpace(tag)), strings.TrimSpace(value), true
+}
+
+// SubmissionDocuments returns an iterator over the documents of the complete
+// submission text file of the EDGAR index entry.
+//
+// SubmissionDocuments is a wrapper around DefaultClient.SubmissionDocuments.
+func SubmissionDocuments(ctx context.Context, e EDGARIndexEntry) iter.Seq2[*SubmissionDocument, error] {
+	return DefaultClient.SubmissionDocuments(ctx, e)
+}
+
+// SubmissionDocuments returns an iterator over the documents of the complete
+// submission text file of the EDGAR index entry. The file is fetched when the
+// loop starts and closed when it ends.
+func (c *Client) SubmissionDocuments(ctx context.Context, e EDGARIndexEntry) iter.Seq2[*SubmissionDocument, error] {
+	return func(yield func(*SubmissionDocument, error) bool) {
+		// Use DefaultClient when nil.
+		if c == nil {
+			c = DefaultClient
+		}
+
+		resp, err := c.get(ctx, c.EDGARIndexEntryURL(e))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		for doc, err := range ParseSubmissionDocuments(resp.Body) {
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+				yield(nil, err)
+				return
+			}
+			if !yield(doc, nil) {
+				return
+			}
+		}
+	}
+}
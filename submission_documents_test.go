@@ -0,0 +1,145 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSubmissionDocuments(t *testing.T) {
+	longLine := strings.Repeat("x", 100000)
+	s := `<SEC-DOCUMENT>0000000000-18-000001.txt : 20181015
+<SEC-HEADER>0000000000-18-000001.hdr.sgml : 20181015
+</SEC-HEADER>
+<DOCUMENT>
+<TYPE>8-K
+<SEQUENCE>1
+<FILENAME>form8-k.htm
+<DESCRIPTION>FORM 8-K
+<TEXT>
+<html>` + longLine + `</html>
+</TEXT>
+</DOCUMENT>
+<DOCUMENT>
+<TYPE>EX-99.1
+<SEQUENCE>2
+<FILENAME>ex99-1.htm
+<text><html>press release</html></text>
+</DOCUMENT>
+<DOCUMENT>
+<TYPE>EX-101.INS
+<SEQUENCE>3
+<FILENAME>doc.xml
+<DESCRIPTION>XBRL INSTANCE DOCUMENT
+<TEXT>
+<XBRL>
+<xbrl/>
+</XBRL>
+</TEXT>
+</DOCUMENT>
+</SEC-DOCUMENT>
+`
+
+	type document struct {
+		Type, Filename, Description, Text string
+		Sequence                          int
+	}
+	var got []document
+	for doc, err := range ParseSubmissionDocuments(strings.NewReader(s)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := document{Type: doc.Type, Sequence: doc.Sequence, Filename: doc.Filename, Description: doc.Description}
+		// Leave the text of the first document unread.
+		if doc.Sequence > 1 {
+			b, err := io.ReadAll(doc.Text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Text = string(b)
+		}
+		got = append(got, d)
+	}
+	if want := []document{
+		{Type: "8-K", Sequence: 1, Filename: "form8-k.htm", Description: "FORM 8-K"},
+		{Type: "EX-99.1", Sequence: 2, Filename: "ex99-1.htm", Text: "<html>press release</html>"},
+		{Type: "EX-101.INS", Sequence: 3, Filename: "doc.xml", Description: "XBRL INSTANCE DOCUMENT", Text: "<XBRL>\n<xbrl/>\n</XBRL>"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// Long lines are read in full.
+	for doc, err := range ParseSubmissionDocuments(strings.NewReader(s)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(doc.Text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "<html>" + longLine + "</html>"; string(b) != want {
+			t.Fatalf("got %d bytes, want %d", len(b), len(want))
+		}
+		break
+	}
+
+	// Truncated documents are reported.
+	var err error
+	for doc, docErr := range ParseSubmissionDocuments(strings.NewReader("<DOCUMENT>\n<TYPE>4\n<TEXT>\n<XML>\n")) {
+		if err = docErr; err != nil {
+			break
+		}
+		_, err = io.ReadAll(doc.Text)
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("got error %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestClient_SubmissionDocuments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleForm4SECDocument))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL))
+	e := EDGARIndexEntry{Filename: "edgar/data/1000045/0001357521-18-000008.txt"}
+	n := 0
+	for doc, err := range c.SubmissionDocuments(context.Background(), e) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+		if doc.Type != "4" || doc.Filename != "primary_doc.xml" {
+			t.Fatalf("got document %+v", doc)
+		}
+		form, err := ParseForm4FromSECDocument(doc.Text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.IssuerCIK != 1000045 {
+			t.Fatalf("got issuer CIK %d, want 1000045", form.IssuerCIK)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("got %d documents, want 1", n)
+	}
+}
@@ -0,0 +1,516 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A SubmissionHeader represents the SEC-HEADER of an EDGAR submission.
+type SubmissionHeader struct {
+	AccessionNumber     AccessionNumber
+	SubmissionType      string
+	PublicDocumentCount int
+	PeriodOfReport      time.Time
+	FiledAsOfDate       time.Time
+	DateAsOfChange      time.Time
+	EffectivenessDate   time.Time
+
+	// AcceptanceDatetime is when EDGAR accepted the submission, in Eastern
+	// Time.
+	AcceptanceDatetime time.Time
+
+	// Items are the items reported by the submission, such as the items of a
+	// form 8-K filing.
+	Items []string
+
+	GroupMembers []string
+
+	Filers           []SubmissionEntity
+	Issuers          []SubmissionEntity
+	ReportingOwners  []SubmissionEntity
+	SubjectCompanies []SubmissionEntity
+	FiledBy          []SubmissionEntity
+}
+
+// A SubmissionEntity represents a company or owner in the SEC-HEADER of an
+// EDGAR submission.
+type SubmissionEntity struct {
+	Name                 string
+	CIK                  int
+	SICCode              int
+	SICDescription       string
+	OrganizationName     string
+	IRSNumber            string
+	StateOfIncorporation string
+
+	// FiscalYearEnd is the month and day the fiscal year ends, e.g. "0331".
+	FiscalYearEnd string
+
+	FilingValues    SubmissionFilingValues
+	BusinessAddress SubmissionAddress
+	MailAddress     SubmissionAddress
+	FormerNames     []SubmissionFormerName
+}
+
+// SubmissionFilingValues represents the filing values of a company or owner in
+// the SEC-HEADER of an EDGAR submission.
+type SubmissionFilingValues struct {
+	FormType      string
+	SECAct        string
+	SECFileNumber string
+	FilmNumber    string
+}
+
+// A SubmissionAddress represents an address in the SEC-HEADER of an EDGAR
+// submission.
+type SubmissionAddress struct {
+	Street1 string
+	Street2 string
+	City    string
+	State   string
+	Zip     string
+	Phone   string
+}
+
+// A SubmissionFormerName represents a former name of a company or owner in the
+// SEC-HEADER of an EDGAR submission.
+type SubmissionFormerName struct {
+	Name         string
+	DateOfChange time.Time
+}
+
+// easternTime is the time zone of EDGAR acceptance times. It falls back to
+// Eastern Standard Time when the time zone database is unavailable.
+var easternTime = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}()
+
+// ParseSubmissionHeader parses the SEC-HEADER of an EDGAR submission read from
+// r, such as a complete submission text file, or its SUBMISSION tag, such as a
+// .hdr.sgml file. Reading stops at the end of the header, so when r is a
+// *bufio.Reader the documents of the submission can be read from it
+// afterwards.
+func ParseSubmissionHeader(r io.Reader) (*SubmissionHeader, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	h, _, err := parseSubmissionHeader(br)
+	return h, err
+}
+
+// parseSubmissionHeader parses the header of an EDGAR submission read from br,
+// returning whether its end was read.
+func parseSubmissionHeader(br *bufio.Reader) (*SubmissionHeader, bool, error) {
+	h := &SubmissionHeader{}
+	found := false
+	var entity *SubmissionEntity
+	var section string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		text := strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimSpace(text)
+
+		if tag, value, ok := parseSGMLTag(trimmed); ok {
+			switch tag {
+			case "SEC-HEADER", "IMS-HEADER":
+				found = true
+			case "/SEC-HEADER", "/IMS-HEADER", "DOCUMENT":
+				if !found {
+					return nil, false, errMissingSubmissionHeader
+				}
+				return h, true, nil
+			case "SUBMISSION":
+				complete, err := h.parseTagged(br)
+				return h, complete, err
+			case "ACCEPTANCE-DATETIME":
+				if err := h.setAcceptanceDatetime(value); err != nil {
+					return nil, false, err
+				}
+			}
+		} else if key, value, ok := strings.Cut(trimmed, ":"); ok {
+			found = true
+			key, value = strings.ToUpper(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+			// Fields of entities are indented under them, and entities
+			// are followed by other fields of the submission.
+			if text[0] != ' ' && text[0] != '\t' {
+				entity, section = h.startEntity(key), ""
+				if entity == nil {
+					if err := h.setField(key, value); err != nil {
+						return nil, false, err
+					}
+				}
+			} else if entity != nil {
+				if s, ok := entity.startSection(key); ok {
+					section = s
+				} else if err := entity.setField(section, key, value); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+	if !found {
+		return nil, false, errMissingSubmissionHeader
+	}
+	return h, false, nil
+}
+
+// errMissingSubmissionHeader is returned when parsing a submission without a
+// header.
+var errMissingSubmissionHeader = errors.New("sec.ParseSubmissionHeader: missing SEC header")
+
+// taggedSubmissionHeaderKeys maps the tags of a SUBMISSION tag to the keys of
+// the equivalent SEC-HEADER fields. Other tags map to their name with spaces
+// for dashes.
+var taggedSubmissionHeaderKeys = map[string]string{
+	"TYPE":                       "CONFORMED SUBMISSION TYPE",
+	"PERIOD":                     "CONFORMED PERIOD OF REPORT",
+	"FILING-DATE":                "FILED AS OF DATE",
+	"DATE-OF-FILING-DATE-CHANGE": "DATE AS OF CHANGE",
+	"ITEMS":                      "ITEM INFORMATION",
+	"REPORTING-OWNER":            "REPORTING-OWNER",
+	"CONFORMED-NAME":             "COMPANY CONFORMED NAME",
+	"CIK":                        "CENTRAL INDEX KEY",
+	"ACT":                        "SEC ACT",
+	"FILE-NUMBER":                "SEC FILE NUMBER",
+	"STREET1":                    "STREET 1",
+	"STREET2":                    "STREET 2",
+	"DATE-CHANGED":               "DATE OF NAME CHANGE",
+}
+
+// parseTagged parses the content of a SUBMISSION tag read from br into h,
+// returning whether its end was read. Entities and their sections are tags
+// without a value containing their fields.
+func (h *SubmissionHeader) parseTagged(br *bufio.Reader) (bool, error) {
+	var entity *SubmissionEntity
+	var section string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+
+		if tag, value, ok := parseSGMLTag(line); ok {
+			key, ok := taggedSubmissionHeaderKeys[strings.TrimPrefix(tag, "/")]
+			if !ok {
+				key = strings.ReplaceAll(strings.TrimPrefix(tag, "/"), "-", " ")
+			}
+
+			switch {
+			case tag == "/SUBMISSION":
+				return true, nil
+			case tag == "ACCEPTANCE-DATETIME":
+				if err := h.setAcceptanceDatetime(value); err != nil {
+					return false, err
+				}
+			case strings.HasPrefix(tag, "/"):
+				// Close the entity or section.
+				if h.entities(key) != nil {
+					entity, section = nil, ""
+				} else if key == section || key == "FORMER COMPANY" {
+					section = ""
+				}
+			case value == "":
+				if e := h.startEntity(key); e != nil {
+					entity, section = e, ""
+				} else if entity != nil {
+					if s, ok := entity.startSection(key); ok {
+						section = s
+					}
+				}
+			case entity == nil:
+				if err := h.setField(key, value); err != nil {
+					return false, err
+				}
+			case tag == "ASSIGNED-SIC":
+				if entity.SICCode, err = strconv.Atoi(value); err != nil {
+					return false, err
+				}
+			default:
+				if err := entity.setField(section, key, value); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return false, nil
+		}
+	}
+}
+
+// setAcceptanceDatetime sets the acceptance datetime of h to s, e.g.
+// "20181015171243".
+func (h *SubmissionHeader) setAcceptanceDatetime(s string) error {
+	t, err := time.ParseInLocation("20060102150405", s, easternTime)
+	if err != nil {
+		return err
+	}
+	h.AcceptanceDatetime = t
+	return nil
+}
+
+// startEntity appends an entity to h for key, returning nil if key is not an
+// entity.
+func (h *SubmissionHeader) startEntity(key string) *SubmissionEntity {
+	entities := h.entities(key)
+	if entities == nil {
+		return nil
+	}
+	*entities = append(*entities, SubmissionEntity{})
+	return &(*entities)[len(*entities)-1]
+}
+
+// startSection returns the section of e started by key, if any.
+func (e *SubmissionEntity) startSection(key string) (string, bool) {
+	switch key {
+	case "COMPANY DATA", "OWNER DATA", "FILING VALUES", "BUSINESS ADDRESS", "MAIL ADDRESS":
+		return key, true
+	case "FORMER COMPANY", "FORMER NAME":
+		e.FormerNames = append(e.FormerNames, SubmissionFormerName{})
+		return "FORMER NAME", true
+	}
+	return "", false
+}
+
+// entities returns the entities of h for key, or nil if key is not an entity.
+func (h *SubmissionHeader) entities(key string) *[]SubmissionEntity {
+	switch key {
+	case "FILER":
+		return &h.Filers
+	case "ISSUER":
+		return &h.Issuers
+	case "REPORTING-OWNER":
+		return &h.ReportingOwners
+	case "SUBJECT COMPANY":
+		return &h.SubjectCompanies
+	case "FILED BY":
+		return &h.FiledBy
+	}
+	return nil
+}
+
+// setField sets the field of h for key to value, ignoring unknown keys.
+func (h *SubmissionHeader) setField(key, value string) error {
+	var err error
+	switch key {
+	case "ACCESSION NUMBER":
+		h.AccessionNumber, err = ParseAccessionNumber(value)
+	case "CONFORMED SUBMISSION TYPE":
+		h.SubmissionType = value
+	case "PUBLIC DOCUMENT COUNT":
+		h.PublicDocumentCount, err = strconv.Atoi(value)
+	case "CONFORMED PERIOD OF REPORT":
+		h.PeriodOfReport, err = parseEDGARIndexDate(value)
+	case "FILED AS OF DATE":
+		h.FiledAsOfDate, err = parseEDGARIndexDate(value)
+	case "DATE AS OF CHANGE":
+		h.DateAsOfChange, err = parseEDGARIndexDate(value)
+	case "EFFECTIVENESS DATE":
+		h.EffectivenessDate, err = parseEDGARIndexDate(value)
+	case "ITEM INFORMATION":
+		h.Items = append(h.Items, value)
+	case "GROUP MEMBERS":
+		h.GroupMembers = append(h.GroupMembers, value)
+	}
+	return err
+}
+
+// setField sets the field of e for key in section to value, ignoring unknown
+// keys.
+func (e *SubmissionEntity) setField(section, key, value string) error {
+	var err error
+	switch section {
+	case "COMPANY DATA", "OWNER DATA":
+		switch key {
+		case "COMPANY CONFORMED NAME":
+			e.Name = value
+		case "CENTRAL INDEX KEY":
+			e.CIK, err = strconv.Atoi(value)
+		case "STANDARD INDUSTRIAL CLASSIFICATION":
+			e.SICCode, e.SICDescription, err = parseSIC(value)
+		case "ORGANIZATION NAME":
+			e.OrganizationName = value
+		case "IRS NUMBER":
+			e.IRSNumber = value
+		case "STATE OF INCORPORATION":
+			e.StateOfIncorporation = value
+		case "FISCAL YEAR END":
+			e.FiscalYearEnd = value
+		}
+	case "FILING VALUES":
+		switch key {
+		case "FORM TYPE":
+			e.FilingValues.FormType = value
+		case "SEC ACT":
+			e.FilingValues.SECAct = value
+		case "SEC FILE NUMBER":
+			e.FilingValues.SECFileNumber = value
+		case "FILM NUMBER":
+			e.FilingValues.FilmNumber = value
+		}
+	case "BUSINESS ADDRESS":
+		e.BusinessAddress.setField(key, value)
+	case "MAIL ADDRESS":
+		e.MailAddress.setField(key, value)
+	case "FORMER NAME":
+		former := &e.FormerNames[len(e.FormerNames)-1]
+		switch key {
+		case "FORMER CONFORMED NAME":
+			former.Name = value
+		case "DATE OF NAME CHANGE":
+			former.DateOfChange, err = parseEDGARIndexDate(value)
+		}
+	}
+	return err
+}
+
+// setField sets the field of a for key to value, ignoring unknown keys.
+func (a *SubmissionAddress) setField(key, value string) {
+	switch key {
+	case "STREET 1":
+		a.Street1 = value
+	case "STREET 2":
+		a.Street2 = value
+	case "CITY":
+		a.City = value
+	case "STATE":
+		a.State = value
+	case "ZIP":
+		a.Zip = value
+	case "BUSINESS PHONE", "PHONE":
+		a.Phone = value
+	}
+}
+
+// parseSIC parses a standard industrial classification like "SHORT-TERM
+// BUSINESS CREDIT INSTITUTIONS [6153]".
+func parseSIC(s string) (int, string, error) {
+	i := strings.LastIndex(s, "[")
+	if i < 0 || !strings.HasSuffix(s, "]") {
+		return 0, "", errors.New("sec.ParseSubmissionHeader: invalid standard industrial classification " + strconv.Quote(s))
+	}
+	description := strings.TrimSpace(s[:i])
+	code := s[i+1 : len(s)-1]
+	if code == "" {
+		return 0, description, nil
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, description, nil
+}
+
+// submissionHeaderRangeSize is the size of the range of a complete submission
+// text file requested for its header.
+const submissionHeaderRangeSize = 64 << 10
+
+// GetSubmissionHeader gets the header of the filing of company cik.
+//
+// GetSubmissionHeader is a wrapper around DefaultClient.GetSubmissionHeader.
+func GetSubmissionHeader(ctx context.Context, cik int, a AccessionNumber) (*SubmissionHeader, error) {
+	return DefaultClient.GetSubmissionHeader(ctx, cik, a)
+}
+
+// GetSubmissionHeader gets the header of the filing of company cik without its
+// documents. The header is read from the .hdr.sgml file of the filing, falling
+// back to a range request for the start of its complete submission text file.
+func (c *Client) GetSubmissionHeader(ctx context.Context, cik int, a AccessionNumber) (*SubmissionHeader, error) {
+	// Use DefaultClient when nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	h, err := c.getSubmissionHeader(ctx, c.FilingHeaderURL(cik, a), false)
+	if err == nil {
+		return h, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return c.getSubmissionHeader(ctx, c.SubmissionURL(cik, a), true)
+}
+
+// getSubmissionHeader gets the header of the submission at url. When ranged,
+// only the start of the submission is requested unless the header is longer or
+// cannot be parsed from it.
+func (c *Client) getSubmissionHeader(ctx context.Context, url string, ranged bool) (*SubmissionHeader, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", submissionHeaderRangeSize-1))
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Drop the line cut by the end of the range, which is read again with the
+	// whole file when the header is incomplete.
+	var r io.Reader = resp.Body
+	partial := resp.StatusCode == http.StatusPartialContent
+	if partial {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+		r = bytes.NewReader(b[:bytes.LastIndexByte(b, '\n')+1])
+	}
+
+	h, complete, err := parseSubmissionHeader(bufio.NewReader(r))
+	if partial && (err != nil || !complete) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		resp.Body.Close()
+		return c.getSubmissionHeader(ctx, url, false)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,302 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSubmissionHeader(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader(sampleForm4SECDocument))
+	got, err := ParseSubmissionHeader(br)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&SubmissionHeader{
+		AccessionNumber:     AccessionNumber{FilerCIK: 1357521, Year: 18, Sequence: 8},
+		SubmissionType:      "4",
+		PublicDocumentCount: 1,
+		PeriodOfReport:      time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		FiledAsOfDate:       time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		DateAsOfChange:      time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC),
+		AcceptanceDatetime:  time.Date(2018, 10, 15, 17, 12, 43, 0, easternTime),
+		ReportingOwners: []SubmissionEntity{
+			{
+				Name: "MALSON KELLY M",
+				CIK:  1357521,
+				FilingValues: SubmissionFilingValues{
+					FormType:      "4",
+					SECAct:        "1934 Act",
+					SECFileNumber: "000-26680",
+					FilmNumber:    "181122886",
+				},
+				MailAddress: SubmissionAddress{
+					Street1: "2454 MCMULLEN BOOTH ROAD",
+					Street2: "BUILDING C",
+					City:    "CLEARWATER",
+					State:   "FL",
+					Zip:     "33759",
+				},
+				FormerNames: []SubmissionFormerName{
+					{
+						Name:         "Snape Kelly Malson",
+						DateOfChange: time.Date(2006, 3, 27, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		Issuers: []SubmissionEntity{
+			{
+				Name:                 "NICHOLAS FINANCIAL INC",
+				CIK:                  1000045,
+				SICCode:              6153,
+				SICDescription:       "SHORT-TERM BUSINESS CREDIT INSTITUTIONS",
+				IRSNumber:            "593019317",
+				StateOfIncorporation: "FL",
+				FiscalYearEnd:        "0331",
+				BusinessAddress: SubmissionAddress{
+					Street1: "2454 MCMULLEN BOOTH RD",
+					Street2: "BLDG C SUITE 501 B",
+					City:    "CLEARWATER",
+					State:   "FL",
+					Zip:     "33759",
+					Phone:   "7277260763",
+				},
+				MailAddress: SubmissionAddress{
+					Street1: "2454 MCMULLEN BOOTH RD",
+					Street2: "BLDG C SUITE 501B",
+					City:    "CLEARWATER",
+					State:   "FL",
+					Zip:     "33759",
+				},
+			},
+		},
+	}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// The documents of the submission follow the header.
+	line, err := br.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "<DOCUMENT>\n" {
+		t.Fatalf("got line %q after the header, want <DOCUMENT>", line)
+	}
+}
+
+func TestParseSubmissionHeaderItems(t *testing.T) {
+	got, err := ParseSubmissionHeader(strings.NewReader(`<SEC-HEADER>0000320193-18-000144.hdr.sgml : 20181105
+ACCESSION NUMBER:		0000320193-18-000144
+CONFORMED SUBMISSION TYPE:	8-K
+ITEM INFORMATION:		Results of Operations and Financial Condition
+ITEM INFORMATION:		Financial Statements and Exhibits
+
+FILER:
+
+	COMPANY DATA:
+		COMPANY CONFORMED NAME:			APPLE INC
+		CENTRAL INDEX KEY:			0000320193
+
+	FORMER COMPANY:
+		FORMER CONFORMED NAME:	APPLE COMPUTER INC
+		DATE OF NAME CHANGE:	19970808
+GROUP MEMBERS:		APPLE OPERATIONS INTERNATIONAL
+</SEC-HEADER>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Results of Operations and Financial Condition", "Financial Statements and Exhibits"}; !reflect.DeepEqual(got.Items, want) {
+		t.Fatalf("got items %q, want %q", got.Items, want)
+	}
+	if want := []string{"APPLE OPERATIONS INTERNATIONAL"}; !reflect.DeepEqual(got.GroupMembers, want) {
+		t.Fatalf("got group members %q, want %q", got.GroupMembers, want)
+	}
+	if len(got.Filers) != 1 || got.Filers[0].CIK != 320193 || len(got.Filers[0].FormerNames) != 1 || got.Filers[0].FormerNames[0].Name != "APPLE COMPUTER INC" {
+		t.Fatalf("got filers %+v", got.Filers)
+	}
+
+	if _, err := ParseSubmissionHeader(strings.NewReader("<DOCUMENT>\n")); err == nil {
+		t.Fatal("got no error for a missing header")
+	}
+}
+
+// sampleSubmissionHeaderSGML is the .hdr.sgml form of the header of
+// sampleForm4SECDocument.
+const sampleSubmissionHeaderSGML = `<SUBMISSION>
+<ACCEPTANCE-DATETIME>20181015171243
+<ACCESSION-NUMBER>0001357521-18-000008
+<TYPE>4
+<PUBLIC-DOCUMENT-COUNT>1
+<PERIOD>20181015
+<FILING-DATE>20181015
+<DATE-OF-FILING-DATE-CHANGE>20181015
+<REPORTING-OWNER>
+<OWNER-DATA>
+<CONFORMED-NAME>MALSON KELLY M
+<CIK>0001357521
+</OWNER-DATA>
+<FILING-VALUES>
+<FORM-TYPE>4
+<ACT>34
+<FILE-NUMBER>000-26680
+<FILM-NUMBER>181122886
+</FILING-VALUES>
+<MAIL-ADDRESS>
+<STREET1>2454 MCMULLEN BOOTH ROAD
+<STREET2>BUILDING C
+<CITY>CLEARWATER
+<STATE>FL
+<ZIP>33759
+</MAIL-ADDRESS>
+<FORMER-NAME>
+<FORMER-CONFORMED-NAME>Snape Kelly Malson
+<DATE-CHANGED>20060327
+</FORMER-NAME>
+</REPORTING-OWNER>
+<ISSUER>
+<COMPANY-DATA>
+<CONFORMED-NAME>NICHOLAS FINANCIAL INC
+<CIK>0001000045
+<ASSIGNED-SIC>6153
+<IRS-NUMBER>593019317
+<STATE-OF-INCORPORATION>FL
+<FISCAL-YEAR-END>0331
+</COMPANY-DATA>
+<BUSINESS-ADDRESS>
+<STREET1>2454 MCMULLEN BOOTH RD
+<STREET2>BLDG C SUITE 501 B
+<CITY>CLEARWATER
+<STATE>FL
+<ZIP>33759
+<PHONE>7277260763
+</BUSINESS-ADDRESS>
+<MAIL-ADDRESS>
+<STREET1>2454 MCMULLEN BOOTH RD
+<STREET2>BLDG C SUITE 501B
+<CITY>CLEARWATER
+<STATE>FL
+<ZIP>33759
+</MAIL-ADDRESS>
+</ISSUER>
+</SUBMISSION>
+`
+
+func TestParseSubmissionHeaderSGML(t *testing.T) {
+	got, err := ParseSubmissionHeader(strings.NewReader(sampleSubmissionHeaderSGML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tagged header lacks the SIC description and abbreviates the act.
+	want, err := ParseSubmissionHeader(strings.NewReader(sampleForm4SECDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.ReportingOwners[0].FilingValues.SECAct = "34"
+	want.Issuers[0].SICDescription = ""
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_GetSubmissionHeader(t *testing.T) {
+	submission := sampleForm4SECDocument
+	hdrSGML := true
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/edgar/data/1000045/000135752118000008/0001357521-18-000008.hdr.sgml":
+			if hdrSGML {
+				w.Write([]byte(sampleSubmissionHeaderSGML))
+				return
+			}
+		case "/edgar/data/1000045/0001357521-18-000008.txt":
+			ranges = append(ranges, r.Header.Get("Range"))
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(submission))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL))
+	a := AccessionNumber{FilerCIK: 1357521, Year: 18, Sequence: 8}
+	h, err := c.GetSubmissionHeader(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Issuers[0].SICCode != 6153 || ranges != nil {
+		t.Fatalf("got header %+v and ranges %q, want the .hdr.sgml header", h, ranges)
+	}
+
+	// Fall back to the start of the complete submission text file.
+	hdrSGML = false
+	h, err = c.GetSubmissionHeader(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Issuers[0].SICDescription != "SHORT-TERM BUSINESS CREDIT INSTITUTIONS" {
+		t.Fatalf("got header %+v, want the header of the submission", h)
+	}
+	if want := []string{"bytes=0-65535"}; !reflect.DeepEqual(ranges, want) {
+		t.Fatalf("got ranges %q, want %q", ranges, want)
+	}
+
+	// Request the whole file when the header is longer than the range.
+	members := strings.Repeat("GROUP MEMBERS:\t\tMEMBER\n", 5000)
+	submission = strings.Replace(sampleForm4SECDocument, "\nREPORTING-OWNER:", "\n"+members+"REPORTING-OWNER:", 1)
+	ranges = nil
+	h, err = c.GetSubmissionHeader(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.GroupMembers) != 5000 || len(h.Issuers) != 1 {
+		t.Fatalf("got %d group members and %d issuers, want 5000 and 1", len(h.GroupMembers), len(h.Issuers))
+	}
+	if want := []string{"bytes=0-65535", ""}; !reflect.DeepEqual(ranges, want) {
+		t.Fatalf("got ranges %q, want %q", ranges, want)
+	}
+
+	// Request the whole file when the range ends inside a line, here after
+	// "201" of the date filed.
+	i := strings.Index(sampleForm4SECDocument, "FILED AS OF DATE:")
+	fill := submissionHeaderRangeSize - len(sampleForm4SECDocument[:i]+"FILED AS OF DATE:\t\t201")
+	members = ""
+	for len(members)+200 < fill {
+		members += "GROUP MEMBERS:\t" + strings.Repeat("M", 84) + "\n"
+	}
+	members += "GROUP MEMBERS:\t" + strings.Repeat("M", fill-len(members)-len("GROUP MEMBERS:\t\n")) + "\n"
+	submission = sampleForm4SECDocument[:i] + members + sampleForm4SECDocument[i:]
+	ranges = nil
+	h, err = c.GetSubmissionHeader(context.Background(), 1000045, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC); !h.FiledAsOfDate.Equal(want) {
+		t.Fatalf("got date filed %v, want %v", h.FiledAsOfDate, want)
+	}
+	if want := []string{"bytes=0-65535", ""}; !reflect.DeepEqual(ranges, want) {
+		t.Fatalf("got ranges %q, want %q", ranges, want)
+	}
+}
@@ -16,60 +16,170 @@ package sec
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
 )
 
-// tagFromSECDocumentReader reads a tag from an SEC document.
+// maxTagSize is the maximum size of a tag in an SEC document, including any
+// whitespace around its name.
+const maxTagSize = 256
+
+// tagFromSECDocumentReader reads the content of a tag from an SEC document up
+// to its close tag.
 type tagFromSECDocumentReader struct {
-	scanner *bufio.Scanner
-	tag     string
+	br  *bufio.Reader
+	tag string
+	err error
 }
 
 // Read implements the io.Reader interface.
-func (r *tagFromSECDocumentReader) Read(p []byte) (n int, err error) {
-	if r.scanner == nil {
-		return 0, io.EOF
+func (r *tagFromSECDocumentReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
 	}
 
-	if !r.scanner.Scan() {
-		if err := r.scanner.Err(); err != nil {
-			return 0, err
+	n := 0
+	for n < len(p) && r.err == nil {
+		// Return what was read rather than waiting for more.
+		if n > 0 && r.br.Buffered() == 0 {
+			break
+		}
+		if _, err := r.br.Peek(1); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			r.err = err
+			break
+		}
+		buf, _ := r.br.Peek(r.br.Buffered())
+
+		// Copy everything before the next byte that may start the close
+		// tag or the newline preceding it.
+		i := bytes.IndexAny(buf, "<\r\n")
+		if i < 0 {
+			i = len(buf)
 		}
-		return 0, io.EOF
+		if i > 0 {
+			k := copy(p[n:], buf[:i])
+			r.br.Discard(k)
+			n += k
+			continue
+		}
+
+		if size, ok := r.matchCloseTag(); ok {
+			r.br.Discard(size)
+			r.err = io.EOF
+			break
+		}
+		// Peeking for the close tag may have moved buf.
+		p[n], _ = r.br.ReadByte()
+		n++
 	}
+	if n > 0 {
+		return n, nil
+	}
+	return 0, r.err
+}
+
+// matchCloseTag returns the size of the close tag at the start of the buffer,
+// including a newline preceding it, if any.
+func (r *tagFromSECDocumentReader) matchCloseTag() (int, bool) {
+	b, _ := r.br.Peek(maxTagSize)
+	newline := 0
+	if bytes.HasPrefix(b, []byte("\r\n")) {
+		newline = 2
+	} else if bytes.HasPrefix(b, []byte("\n")) {
+		newline = 1
+	}
+	size, ok := matchTag(b[newline:], r.tag, true)
+	return newline + size, ok
+}
 
-	// Read until the tag ends.
-	if strings.TrimSpace(r.scanner.Text()) == "</"+r.tag+">" {
-		r.scanner = nil
-		return 0, io.EOF
+// matchTag returns the size of the tag at the start of b if it is the named
+// tag, e.g. "<XML>", or its close tag, e.g. "</XML>". Names are matched
+// case-insensitively and may be surrounded by whitespace.
+func matchTag(b []byte, name string, close bool) (int, bool) {
+	if len(b) == 0 || b[0] != '<' {
+		return 0, false
 	}
+	i := skipSpace(b, 1)
+	if close {
+		if i >= len(b) || b[i] != '/' {
+			return 0, false
+		}
+		i = skipSpace(b, i+1)
+	}
+	if len(b)-i < len(name) || !bytes.EqualFold(b[i:i+len(name)], []byte(name)) {
+		return 0, false
+	}
+	i = skipSpace(b, i+len(name))
+	if i >= len(b) || b[i] != '>' {
+		return 0, false
+	}
+	return i + 1, true
+}
 
-	copy(p, r.scanner.Bytes())
-	return len(r.scanner.Bytes()), nil
+// skipSpace returns the index of the first byte of b from i that is not
+// whitespace.
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\r' || b[i] == '\n') {
+		i++
+	}
+	return i
 }
 
 // ExtractTagFromSECDocument extracts a tag from an SEC document read from r,
 // returning a reader to the tag content.
+//
+// The content is read byte for byte, except for a newline directly after the
+// tag and directly before its close tag. Tags are matched case-insensitively,
+// may have whitespace around their name and may share a line with content.
+// Reading the content fails with io.ErrUnexpectedEOF when the close tag is
+// missing.
 func ExtractTagFromSECDocument(r io.Reader, tag string) (io.Reader, error) {
-	scanner := bufio.NewScanner(r)
+	tag = strings.TrimSpace(tag)
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
 
 	// Skip until the tag starts.
-	found := false
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "<"+tag+">" {
-			found = true
+	for {
+		if _, err := br.Peek(1); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf(
+					"sec.ExtractTagFromSECDocument: missing tag \"%s\"", tag)
+			}
+			return nil, err
+		}
+		buf, _ := br.Peek(br.Buffered())
+		i := bytes.IndexByte(buf, '<')
+		if i < 0 {
+			br.Discard(len(buf))
+			continue
+		}
+		br.Discard(i)
+
+		b, _ := br.Peek(maxTagSize)
+		if size, ok := matchTag(b, tag, false); ok {
+			br.Discard(size)
 			break
 		}
+		br.Discard(1)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-	if !found {
-		return nil, fmt.Errorf(
-			"sec.ExtractTagFromSECDocument: missing tag \"%s\"", tag)
+
+	return newTagFromSECDocumentReader(br, tag), nil
+}
+
+// newTagFromSECDocumentReader returns a reader to the content of the tag that
+// br is positioned after, skipping the newline after the tag.
+func newTagFromSECDocumentReader(br *bufio.Reader, tag string) *tagFromSECDocumentReader {
+	if b, _ := br.Peek(2); bytes.HasPrefix(b, []byte("\r\n")) {
+		br.Discard(2)
+	} else if bytes.HasPrefix(b, []byte("\n")) {
+		br.Discard(1)
 	}
-	return &tagFromSECDocumentReader{scanner, tag}, nil
+	return &tagFromSECDocumentReader{br: br, tag: tag}
 }
@@ -0,0 +1,73 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestExtractTagFromSECDocument(t *testing.T) {
+	longLine := strings.Repeat("<a>x</a>", 1<<17)
+	for _, test := range []struct {
+		name, doc, want string
+	}{
+		{"lines", "<TEXT>\n<XML>\n<a>\n\n  b\t</a>\n</XML>\n</TEXT>\n", "<a>\n\n  b\t</a>"},
+		{"crlf", "<XML>\r\n<a>\r\nb</a>\r\n</XML>\r\n", "<a>\r\nb</a>"},
+		{"same line", "<TEXT><XML><a/></XML></TEXT>", "<a/>"},
+		{"case and whitespace", "< xml >\n<a/>\n</ XML\t>\n", "<a/>"},
+		{"similar tags", "<XMLX>\n<XML>\n<XML2/>\n</XMLX>\n</XML>\n", "<XML2/>\n</XMLX>"},
+		{"blank lines", "<XML>\n\n<a/>\n\n</XML>", "\n<a/>\n"},
+		{"long line", "<XML>\n" + longLine + "\n</XML>\n", longLine},
+	} {
+		for _, oneByte := range []bool{false, true} {
+			var r io.Reader = strings.NewReader(test.doc)
+			if oneByte {
+				r = iotest.OneByteReader(r)
+			}
+			tr, err := ExtractTagFromSECDocument(r, "XML")
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if oneByte {
+				tr = iotest.OneByteReader(tr)
+			}
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if got := string(b); got != test.want {
+				if len(got) > 100 {
+					t.Fatalf("%s: got %d bytes, want %d", test.name, len(got), len(test.want))
+				}
+				t.Fatalf("%s: got %q, want %q", test.name, got, test.want)
+			}
+		}
+	}
+
+	if _, err := ExtractTagFromSECDocument(strings.NewReader("<XMLX>\n</XMLX>\n"), "XML"); err == nil {
+		t.Fatal("got no error for a missing tag")
+	}
+
+	r, err := ExtractTagFromSECDocument(strings.NewReader("<XML>\n<a/>\n"), "XML")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); err != io.ErrUnexpectedEOF {
+		t.Fatalf("got error %v for a missing close tag, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
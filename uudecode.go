@@ -0,0 +1,135 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// uuencodeBegin matches the line beginning uuencoded content, e.g.
+	// "begin 644 exhibit.pdf".
+	uuencodeBegin = regexp.MustCompile(`^begin ([0-7]{3,4}) (.+)$`)
+
+	// uuencodeWrapper matches the tags wrapping uuencoded content in
+	// submissions, e.g. "<PDF>".
+	uuencodeWrapper = regexp.MustCompile(`^</?[A-Za-z]+>$`)
+)
+
+// uuencodePeekSize is the number of bytes peeked at the start of the text of a
+// document to detect uuencoded content.
+const uuencodePeekSize = 512
+
+// detectUuencoded returns the filename and mode of the uuencoded content at the
+// start of br, skipping blank lines and wrapping tags, if any.
+func detectUuencoded(br *bufio.Reader) (string, fs.FileMode, bool) {
+	head, _ := br.Peek(uuencodePeekSize)
+	for {
+		i := bytes.IndexByte(head, '\n')
+		if i < 0 {
+			return "", 0, false
+		}
+		line := strings.TrimSpace(string(head[:i]))
+		head = head[i+1:]
+		if line == "" || uuencodeWrapper.MatchString(line) {
+			continue
+		}
+		m := uuencodeBegin.FindStringSubmatch(line)
+		if m == nil {
+			return "", 0, false
+		}
+		mode, err := strconv.ParseUint(m[1], 8, 32)
+		if err != nil {
+			return "", 0, false
+		}
+		return m[2], fs.FileMode(mode), true
+	}
+}
+
+// uudecoder decodes the uuencoded content read from br, ignoring any lines
+// before the begin line and after the end line.
+type uudecoder struct {
+	br *bufio.Reader
+
+	// buf is the decoded content not read yet.
+	buf []byte
+
+	began bool
+	ended bool
+}
+
+// Read implements the io.Reader interface.
+func (d *uudecoder) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.ended {
+			return 0, io.EOF
+		}
+
+		line, err := d.br.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if !d.began {
+			d.began = uuencodeBegin.MatchString(strings.TrimSpace(line))
+			continue
+		}
+		if line == "" || line == "end" {
+			d.ended = true
+			continue
+		}
+		d.buf = uudecodeLine(d.buf[:0], line)
+		if len(d.buf) == 0 {
+			// A line of no bytes precedes the end line.
+			d.ended = true
+		}
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// uudecodeLine appends the bytes of a uuencoded line to dst. The first
+// character of the line is the number of bytes, followed by 4 characters for
+// every 3 bytes. Missing characters, such as stripped trailing spaces, are
+// decoded as zeros.
+func uudecodeLine(dst []byte, line string) []byte {
+	n := int((line[0] - ' ') & 0x3f)
+	decode := func(i int) byte {
+		if i < len(line) {
+			return (line[i] - ' ') & 0x3f
+		}
+		return 0
+	}
+	for i := 1; n > 0; i += 4 {
+		c0, c1, c2, c3 := decode(i), decode(i+1), decode(i+2), decode(i+3)
+		b := [3]byte{c0<<2 | c1>>4, c1<<4 | c2>>2, c2<<6 | c3}
+		k := min(n, 3)
+		dst = append(dst, b[:k]...)
+		n -= k
+	}
+	return dst
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// uuencode returns b uuencoded as name with mode 644.
+func uuencode(name string, b []byte) string {
+	encode := func(c byte) byte {
+		if c == 0 {
+			return '`'
+		}
+		return c + ' '
+	}
+	var sb strings.Builder
+	sb.WriteString("begin 644 " + name + "\n")
+	for len(b) > 0 {
+		n := min(len(b), 45)
+		line := b[:n]
+		b = b[n:]
+		sb.WriteByte(encode(byte(n)))
+		for i := 0; i < n; i += 3 {
+			var g [3]byte
+			copy(g[:], line[i:])
+			sb.Write([]byte{
+				encode(g[0] >> 2),
+				encode((g[0]<<4 | g[1]>>4) & 0x3f),
+				encode((g[1]<<2 | g[2]>>6) & 0x3f),
+				encode(g[2] & 0x3f),
+			})
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("`\nend\n")
+	return sb.String()
+}
+
+func TestParseSubmissionDocumentsUuencoded(t *testing.T) {
+	pdf := make([]byte, 1000)
+	for i := range pdf {
+		pdf[i] = byte(i * 7)
+	}
+	s := `<DOCUMENT>
+<TYPE>EX-99.1
+<SEQUENCE>1
+<FILENAME>exhibit.pdf
+<TEXT>
+<PDF>
+` + uuencode("exhibit.pdf", pdf) + `</PDF>
+</TEXT>
+</DOCUMENT>
+<DOCUMENT>
+<TYPE>4
+<SEQUENCE>2
+<TEXT>
+begin at the end
+</TEXT>
+</DOCUMENT>
+`
+
+	var docs []*SubmissionDocument
+	var texts [][]byte
+	for doc, err := range ParseSubmissionDocuments(strings.NewReader(s)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(doc.Text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+		texts = append(texts, b)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want 2", len(docs))
+	}
+	if !docs[0].Binary || docs[0].BinaryFilename != "exhibit.pdf" || docs[0].BinaryMode != fs.FileMode(0o644) {
+		t.Fatalf("got document %+v, want a binary exhibit.pdf with mode 644", docs[0])
+	}
+	if !bytes.Equal(texts[0], pdf) {
+		t.Fatalf("got %d decoded bytes, want the %d bytes of the PDF", len(texts[0]), len(pdf))
+	}
+	if docs[1].Binary {
+		t.Fatal("got a binary document for text beginning with begin")
+	}
+	if got, want := string(texts[1]), "begin at the end"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestUudecodeLine(t *testing.T) {
+	// The last line has its trailing space stripped.
+	for _, test := range []struct {
+		line, want string
+	}{
+		{"#0V%T", "Cat"},
+		{"\"0V$", "Ca"},
+		{"#0$!", "@@@"},
+	} {
+		if got := string(uudecodeLine(nil, test.line)); got != test.want {
+			t.Fatalf("%q: got %q, want %q", test.line, got, test.want)
+		}
+	}
+}
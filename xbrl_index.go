@@ -0,0 +1,65 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ParseEDGARXBRLIndex parses an EDGAR XBRL index (xbrl.idx) read from r,
+// calling f for each entry. The XBRL index has the same format as the master
+// index but only lists filings with XBRL financial data.
+func ParseEDGARXBRLIndex(r io.Reader, f func(EDGARIndexEntry) error) error {
+	return (&EDGARIndexParser{Type: XBRLIndex}).Parse(r, f)
+}
+
+// GetXBRLIndexEntries gets the EDGAR XBRL index entries between start and end,
+// calling f for each entry. The end time will default to the current time when
+// zero.
+//
+// GetXBRLIndexEntries is a wrapper around DefaultClient.GetXBRLIndexEntries.
+func GetXBRLIndexEntries(start, end time.Time, f func(EDGARIndexEntry) error) error {
+	return DefaultClient.GetXBRLIndexEntries(start, end, f)
+}
+
+// GetXBRLIndexEntriesContext is like GetXBRLIndexEntries but uses ctx for every
+// request and stops with ctx.Err() when ctx is done.
+//
+// GetXBRLIndexEntriesContext is a wrapper around
+// DefaultClient.GetXBRLIndexEntriesContext.
+func GetXBRLIndexEntriesContext(ctx context.Context, start, end time.Time, f func(EDGARIndexEntry) error) error {
+	return DefaultClient.GetXBRLIndexEntriesContext(ctx, start, end, f)
+}
+
+// GetXBRLIndexEntries gets the EDGAR XBRL index entries between start and end,
+// calling f for each entry. The end time will default to the current time when
+// zero. The XBRL index is always read from the full index, regardless of the
+// client's index type and DailyIndexMode.
+func (c *Client) GetXBRLIndexEntries(start, end time.Time, f func(EDGARIndexEntry) error) error {
+	return c.GetXBRLIndexEntriesContext(context.Background(), start, end, f)
+}
+
+// GetXBRLIndexEntriesContext is like GetXBRLIndexEntries but uses ctx for every
+// request and stops with ctx.Err() when ctx is done.
+func (c *Client) GetXBRLIndexEntriesContext(ctx context.Context, start, end time.Time, f func(EDGARIndexEntry) error) error {
+	// Use DefaultClient if nil.
+	if c == nil {
+		c = DefaultClient
+	}
+
+	return c.getEDGARIndexEntries(ctx, XBRLIndex, IndexQuery{Start: start, End: end}, f)
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Miles Barr <milesbarr2@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sec
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sampleEDGARXBRLIndex = `Description:           XBRL Index of EDGAR Dissemination Feed
+Last Data Received:    December 31, 2018
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+ 
+ 
+ 
+ 
+CIK|Company Name|Form Type|Date Filed|Filename
+--------------------------------------------------------------------------------
+1000045|NICHOLAS FINANCIAL INC|10-Q|2018-11-08|edgar/data/1000045/0001193125-18-322487.txt
+`
+
+func TestClient_GetXBRLIndexEntries(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(sampleEDGARXBRLIndex))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	// The XBRL index is read from the full index even for short ranges.
+	c := NewClient(srv.Client(), WithUserAgent(testUserAgent), WithArchivesURL(srv.URL))
+	start := time.Date(2018, 11, 8, 0, 0, 0, 0, time.UTC)
+	got := []EDGARIndexEntry{}
+	if err := c.GetXBRLIndexEntries(start, start, func(e EDGARIndexEntry) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []EDGARIndexEntry{
+		EDGARIndexEntry{
+			CIK:         1000045,
+			CompanyName: "NICHOLAS FINANCIAL INC",
+			FormType:    "10-Q",
+			DateFiled:   start,
+			Filename:    "edgar/data/1000045/0001193125-18-322487.txt",
+		},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if want := []string{"/edgar/full-index/2018/QTR4/xbrl.gz"}; !reflect.DeepEqual(paths, want) {
+		t.Fatalf("got paths %v, want %v", paths, want)
+	}
+}